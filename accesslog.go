@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// context key for the access log entry of the request being served, see AccessLogJSON
+type accessLogKey struct{}
+
+// context key for the request ID assigned once per request so every log line carries the same one
+type requestIDKey struct{}
+
+// one line of the JSON access log
+type accessLogEntry struct {
+	Method    string  `json:"method"`
+	Path      string  `json:"path"`
+	Template  string  `json:"template"` // empty when no route matched
+	Status    int     `json:"status"`
+	Bytes     int     `json:"bytes"`
+	Duration  float64 `json:"duration"` // milliseconds
+	RequestID string  `json:"request_id"`
+}
+
+func (r *customRouter) accessLogOutput() io.Writer {
+	if r.AccessLogOutput == nil {
+		return os.Stderr
+	}
+	return r.AccessLogOutput
+}
+
+// serves the request, then writes its access log line
+func (r *customRouter) serveWithAccessLog(w http.ResponseWriter, req *http.Request) {
+	start := time.Now()
+	entry := &accessLogEntry{Method: req.Method, Path: req.URL.Path, RequestID: requestID(req)}
+	ctx := context.WithValue(req.Context(), accessLogKey{}, entry)
+	ctx = context.WithValue(ctx, requestIDKey{}, entry.RequestID)
+
+	rec := newStatusRecorder(w)
+	r.serve(rec, req.WithContext(ctx))
+
+	entry.Status = rec.Status()
+	entry.Bytes = rec.bytes
+	entry.Duration = float64(time.Since(start).Microseconds()) / 1000
+	line, err := json.Marshal(entry)
+	if err != nil {
+		r.logger().Errorf("Failed to encode access log entry: %v", err)
+		return
+	}
+	// a single write per line so concurrent requests don't interleave
+	r.accessLogOutput().Write(append(line, '\n'))
+}
+
+// notes the template of the route serving the request in its access log entry, if it has one
+func recordMatchedTemplate(req *http.Request, template string) {
+	if entry, ok := req.Context().Value(accessLogKey{}).(*accessLogEntry); ok {
+		entry.Template = template
+	}
+}
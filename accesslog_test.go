@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAccessLogJSON(t *testing.T) {
+	var out bytes.Buffer
+	router := &customRouter{AccessLogJSON: true, AccessLogOutput: &out}
+
+	var handlerRequestID string
+	router.HandleFunc("/users/%s", func(w http.ResponseWriter, r *http.Request) {
+		handlerRequestID = requestID(r)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("created"))
+	})
+
+	tests := []struct {
+		name     string
+		path     string
+		header   string
+		expected accessLogEntry
+	}{
+		{
+			name:     "matched route",
+			path:     "/users/u42",
+			header:   "req-1",
+			expected: accessLogEntry{Method: "GET", Path: "/users/u42", Template: "/users/%s", Status: 201, Bytes: 7, RequestID: "req-1"},
+		},
+		{
+			name:     "no route",
+			path:     "/missing",
+			header:   "req-2",
+			expected: accessLogEntry{Method: "GET", Path: "/missing", Status: 404, Bytes: 19, RequestID: "req-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out.Reset()
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("X-Request-ID", tt.header)
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			if !strings.HasSuffix(out.String(), "\n") || strings.Count(out.String(), "\n") != 1 {
+				t.Fatalf("expected a single JSON line, got %q", out.String())
+			}
+			var entry accessLogEntry
+			if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+				t.Fatalf("access log line isn't JSON: %v", err)
+			}
+			if entry.Duration < 0 {
+				t.Errorf("unexpected duration %v", entry.Duration)
+			}
+			entry.Duration = 0
+			if entry != tt.expected {
+				t.Errorf("unexpected access log entry: got %+v want %+v", entry, tt.expected)
+			}
+		})
+	}
+
+	t.Run("generated request id shared with the handler", func(t *testing.T) {
+		out.Reset()
+		req, err := http.NewRequest("GET", "/users/u1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		var entry accessLogEntry
+		if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+			t.Fatal(err)
+		}
+		if entry.RequestID == "" || entry.RequestID != handlerRequestID {
+			t.Errorf("access log request id %q differs from the handler's %q", entry.RequestID, handlerRequestID)
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+// SetParamAllowSet restricts the 1-indexed param of the routes registered with template to the keys of set,
+// i.e known tenant IDs. The set is read on every request so it can be updated concurrently while serving;
+// a value not in it makes the route not match, so the request falls through to later routes or a 404.
+func (r *customRouter) SetParamAllowSet(template string, index int, set *sync.Map) error {
+	routes := r.routesForTemplate(template)
+	if len(routes) == 0 {
+		return fmt.Errorf("no route registered for template '%s'", template)
+	}
+
+	for _, rt := range routes {
+		if rt.allowSets == nil {
+			rt.allowSets = make(map[int]*sync.Map)
+		}
+		rt.allowSets[index] = set
+	}
+	return nil
+}
+
+// reports whether every param with an allow set is in it
+func (rt *route) paramsAllowed(params []string) bool {
+	for index, set := range rt.allowSets {
+		if index < 1 || index > len(params) {
+			continue
+		}
+		if _, ok := set.Load(params[index-1]); !ok {
+			return false
+		}
+	}
+	return true
+}
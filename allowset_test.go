@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestSetParamAllowSet(t *testing.T) {
+	var tenants sync.Map
+	tenants.Store("acme", true)
+
+	router := &customRouter{}
+	router.HandleFunc("/tenants/%s/users/%s", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "tenant", getParam(r, 1), "user", getParam(r, 2))
+	})
+	if err := router.SetParamAllowSet("/tenants/%s/users/%s", 1, &tenants); err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		t.Helper()
+		req, err := http.NewRequest("GET", path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("allowed value", func(t *testing.T) {
+		rr := serve("/tenants/acme/users/u1")
+		if rr.Code != http.StatusOK || rr.Body.String() != "tenant acme user u1\n" {
+			t.Errorf("got %d %q", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("denied value", func(t *testing.T) {
+		if rr := serve("/tenants/globex/users/u1"); rr.Code != http.StatusNotFound {
+			t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("live update", func(t *testing.T) {
+		tenants.Store("globex", true)
+		if rr := serve("/tenants/globex/users/u1"); rr.Code != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+		}
+
+		tenants.Delete("acme")
+		if rr := serve("/tenants/acme/users/u1"); rr.Code != http.StatusNotFound {
+			t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("unknown template", func(t *testing.T) {
+		if err := router.SetParamAllowSet("/missing/%s", 1, &tenants); err == nil {
+			t.Error("expected an error for an unregistered template")
+		}
+	})
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// leading template group matching an optional version segment like "/v3"
+const optionalVersionPrefix = "(/v%d)?"
+
+// name of the param capturing the version of an optional version prefix
+const versionParamName = "version"
+
+// returns the version captured by a template's optional "(/v%d)?" prefix, or latest when the request
+// path didn't include one, i.e 3 for "/v3/resource/x" and latest for "/resource/x"
+func apiVersion(r *http.Request, latest int) int {
+	version, err := strconv.Atoi(getParamByName(r, versionParamName))
+	if err != nil {
+		return latest
+	}
+	return version
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOptionalVersionPrefix(t *testing.T) {
+	if result, expected := makeRegexPatternStr("(/v%d)?/resource/%s"), "^(?:/v(?P<version>[0-9]+))?/resource/([a-zA-Z0-9]+)$"; result != expected {
+		t.Errorf("makeRegexPatternStr = %q; want %q", result, expected)
+	}
+
+	router := &customRouter{}
+	router.HandleFunc("(/v%d)?/resource/%s", func(w http.ResponseWriter, r *http.Request) {
+		_, versioned := getParamOK(r, 1)
+		fmt.Fprintf(w, "v%d %s (explicit: %v)\n", apiVersion(r, 4), getParam(r, 2), versioned)
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "versioned",
+			path:           "/v3/resource/r1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "v3 r1 (explicit: true)\n",
+		},
+		{
+			name:           "unversioned defaults to latest",
+			path:           "/resource/r1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "v4 r1 (explicit: false)\n",
+		},
+		{
+			name:           "non-numeric version",
+			path:           "/vx/resource/r1",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
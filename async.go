@@ -0,0 +1,165 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// template of the status route for jobs started by newAsyncHandler, see HandleAsyncStatus
+const asyncStatusTemplate = "/jobs/%s"
+
+// how long the status of a finished job can still be read before it's forgotten
+const asyncJobRetention = time.Hour
+
+// states reported for an async job
+const (
+	jobRunning = "running"
+	jobDone    = "done"
+	jobFailed  = "failed" // the job panicked
+)
+
+// JSON body of the 202 and status responses
+type asyncJobStatus struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+type asyncJob struct {
+	state    string
+	finished time.Time // zero while running
+}
+
+// in-memory record of a router's async jobs by ID; finished jobs are kept for retention so their status
+// can still be read, then evicted
+type asyncJobStore struct {
+	mu        sync.Mutex
+	retention time.Duration
+	now       func() time.Time
+	jobs      map[string]asyncJob
+	lastSweep time.Time
+}
+
+func newAsyncJobStore(retention time.Duration, now func() time.Time) *asyncJobStore {
+	return &asyncJobStore{retention: retention, now: now, jobs: make(map[string]asyncJob), lastSweep: now()}
+}
+
+func (s *asyncJobStore) set(id, state string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.sweep(now)
+	job := asyncJob{state: state}
+	if state != jobRunning {
+		job.finished = now
+	}
+	s.jobs[id] = job
+}
+
+func (s *asyncJobStore) get(id string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok || s.expired(job, s.now()) {
+		return "", false
+	}
+	return job.state, true
+}
+
+func (s *asyncJobStore) expired(job asyncJob, now time.Time) bool {
+	return !job.finished.IsZero() && now.Sub(job.finished) >= s.retention
+}
+
+// evicts finished jobs past the retention period; it runs at most once per retention so recording jobs
+// stays cheap, get already hides the ones awaiting eviction. s.mu must be held.
+func (s *asyncJobStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < s.retention {
+		return
+	}
+	for id, job := range s.jobs {
+		if s.expired(job, now) {
+			delete(s.jobs, id)
+		}
+	}
+	s.lastSweep = now
+}
+
+// returns the router's job store, creating it on first use; like other registration it must not race
+// with serving
+func (r *customRouter) jobStore() *asyncJobStore {
+	if r.asyncJobs == nil {
+		r.asyncJobs = newAsyncJobStore(asyncJobRetention, r.now)
+	}
+	return r.asyncJobs
+}
+
+// creates an http.HandlerFunc for long running operations: it starts job with the params captured from
+// the path in a goroutine and responds 202 Accepted with the job's ID, and a Location header pointing at
+// its status under asyncStatusTemplate, without waiting for it to finish. Jobs are recorded on the router,
+// whose HandleAsyncStatus route reports them for an hour after they finish.
+func (r *customRouter) newAsyncHandler(template string, job func(params []string)) http.HandlerFunc {
+	pathPattern := regexp.MustCompile(makeRegexPatternStr(template))
+	asyncJobs := r.jobStore()
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		matches := pathPattern.FindStringSubmatch(req.URL.Path)
+		if matches == nil {
+			http.NotFound(w, req)
+			return
+		}
+
+		id, err := newJobID()
+		if err != nil {
+			r.logger().Errorf("Error generating a job ID for path '%s': %v", req.URL.Path, err)
+			r.writeError(w, http.StatusInternalServerError, "Internal server error")
+			return
+		}
+
+		asyncJobs.set(id, jobRunning)
+		params := matches[1:]
+		go func() {
+			defer func() {
+				if err := recover(); err != nil {
+					r.logger().Errorf("Async job %s for path '%s' panicked: %v", id, req.URL.Path, err)
+					asyncJobs.set(id, jobFailed)
+				}
+			}()
+			job(params)
+			asyncJobs.set(id, jobDone)
+		}()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", fmt.Sprintf(asyncStatusTemplate, id))
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(asyncJobStatus{ID: id, Status: jobRunning})
+	}
+}
+
+// HandleAsyncStatus registers the asyncStatusTemplate route reporting the state of jobs started by the
+// router's newAsyncHandler handlers; unknown IDs, and jobs finished longer ago than the retention, get a 404
+func (r *customRouter) HandleAsyncStatus() {
+	asyncJobs := r.jobStore()
+	r.handle(asyncStatusTemplate, func(w http.ResponseWriter, req *http.Request) {
+		id := getParam(req, 1)
+		state, ok := asyncJobs.get(id)
+		if !ok {
+			r.writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(asyncJobStatus{ID: id, Status: state})
+	})
+}
+
+func newJobID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
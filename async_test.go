@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewAsyncHandler(t *testing.T) {
+	release := make(chan struct{})
+	ran := make(chan []string, 1)
+
+	router := &customRouter{}
+	router.HandleMethodFunc(http.MethodPost, "/reports/%s", router.newAsyncHandler("/reports/%s", func(params []string) {
+		<-release
+		ran <- params
+	}))
+	router.HandleAsyncStatus()
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(method, path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	status := func(location string) asyncJobStatus {
+		rr := serve(http.MethodGet, location)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("status route returned %d", rr.Code)
+		}
+		var job asyncJobStatus
+		if err := json.Unmarshal(rr.Body.Bytes(), &job); err != nil {
+			t.Fatal(err)
+		}
+		return job
+	}
+
+	rr := serve(http.MethodPost, "/reports/q3")
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusAccepted)
+	}
+	var accepted asyncJobStatus
+	if err := json.Unmarshal(rr.Body.Bytes(), &accepted); err != nil {
+		t.Fatalf("unexpected body %q: %v", rr.Body.String(), err)
+	}
+	location := rr.Header().Get("Location")
+	if accepted.ID == "" || location != "/jobs/"+accepted.ID {
+		t.Fatalf("unexpected job ID %q and Location %q", accepted.ID, location)
+	}
+
+	// the response didn't wait for the job
+	if job := status(location); job.Status != jobRunning {
+		t.Errorf("expected the job running, got %q", job.Status)
+	}
+
+	close(release)
+	select {
+	case params := <-ran:
+		if len(params) != 1 || params[0] != "q3" {
+			t.Errorf("job ran with params %q; want [q3]", params)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("job never ran")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for status(location).Status != jobDone {
+		if time.Now().After(deadline) {
+			t.Fatal("job never reported done")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if rr := serve(http.MethodGet, "/jobs/unknown"); rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown job, got %d", rr.Code)
+	}
+}
+
+func TestAsyncJobStoreRetention(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := newAsyncJobStore(time.Hour, func() time.Time { return now })
+
+	store.set("slow", jobRunning)
+	store.set("finished", jobDone)
+
+	now = now.Add(time.Hour - time.Second)
+	if state, ok := store.get("finished"); !ok || state != jobDone {
+		t.Errorf("finished job within retention: got %q, %v", state, ok)
+	}
+
+	now = now.Add(time.Second)
+	if _, ok := store.get("finished"); ok {
+		t.Error("expected the finished job to be forgotten after the retention period")
+	}
+	if state, ok := store.get("slow"); !ok || state != jobRunning {
+		t.Errorf("running jobs are never evicted: got %q, %v", state, ok)
+	}
+
+	// recording another job sweeps the expired one
+	store.set("next", jobRunning)
+	if _, ok := store.jobs["finished"]; ok || len(store.jobs) != 2 {
+		t.Errorf("expected the finished job to be evicted, store holds %d jobs", len(store.jobs))
+	}
+}
+
+func TestAsyncJobsPerRouter(t *testing.T) {
+	done := make(chan struct{})
+	router := &customRouter{}
+	router.HandleMethodFunc(http.MethodPost, "/reports/%s", router.newAsyncHandler("/reports/%s", func(params []string) {
+		close(done)
+	}))
+	other := &customRouter{}
+	other.HandleAsyncStatus()
+
+	req, err := http.NewRequest(http.MethodPost, "/reports/q3", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	<-done
+
+	req, err = http.NewRequest(http.MethodGet, rr.Header().Get("Location"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr = httptest.NewRecorder()
+	other.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected another router not to see the job, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+// BindPath assigns captured params to the fields of the struct dest points to, using 'path' struct tags
+// holding either a 1-indexed position (`path:"1"`) or a '{name}' placeholder's name (`path:"userID"`).
+// String, bool and integer fields are supported; a missing param or failed conversion is an error.
+func BindPath(r *http.Request, dest any) error {
+	value := reflect.ValueOf(dest)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return errors.New("BindPath: dest must be a non-nil pointer to a struct")
+	}
+
+	target := value.Elem()
+	for i := 0; i < target.NumField(); i++ {
+		field := target.Type().Field(i)
+		tag, ok := field.Tag.Lookup("path")
+		if !ok || !field.IsExported() {
+			continue
+		}
+
+		param, found := lookupParam(r, tag)
+		if !found {
+			return fmt.Errorf("BindPath: field %s: parameter '%s' not captured", field.Name, tag)
+		}
+
+		if err := setFieldFromString(target.Field(i), param); err != nil {
+			return fmt.Errorf("BindPath: field %s: %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+// looks up a param by 1-indexed position if key is numeric, otherwise by name
+func lookupParam(r *http.Request, key string) (string, bool) {
+	if index, err := strconv.Atoi(key); err == nil {
+		return getParamOK(r, index)
+	}
+	return getParamByNameOK(r, key)
+}
+
+// converts the string to the field's kind and assigns it
+func setFieldFromString(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBindPath(t *testing.T) {
+	type postPath struct {
+		UserID string `path:"userID"`
+		PostID int    `path:"2"`
+		Draft  bool   `path:"3"`
+		Ignore string
+	}
+
+	tests := []struct {
+		name          string
+		path          string
+		dest          func() any
+		expected      postPath
+		expectedError string
+	}{
+		{
+			name:     "int and string fields",
+			path:     "/users/u42/posts/17/true",
+			dest:     func() any { return &postPath{} },
+			expected: postPath{UserID: "u42", PostID: 17, Draft: true},
+		},
+		{
+			name:          "bad int conversion",
+			path:          "/users/u42/posts/abc/true",
+			dest:          func() any { return &postPath{} },
+			expectedError: "BindPath: field PostID: strconv.ParseInt: parsing \"abc\": invalid syntax",
+		},
+		{
+			name:          "bad bool conversion",
+			path:          "/users/u42/posts/17/maybe",
+			dest:          func() any { return &postPath{} },
+			expectedError: "BindPath: field Draft: strconv.ParseBool: parsing \"maybe\": invalid syntax",
+		},
+		{
+			name: "missing param",
+			path: "/users/u42/posts/17/true",
+			dest: func() any {
+				return &struct {
+					Comment string `path:"4"`
+				}{}
+			},
+			expectedError: "BindPath: field Comment: parameter '4' not captured",
+		},
+		{
+			name:          "dest not a pointer",
+			path:          "/users/u42/posts/17/true",
+			dest:          func() any { return postPath{} },
+			expectedError: "BindPath: dest must be a non-nil pointer to a struct",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dest := tt.dest()
+			var bindErr error
+
+			router := &customRouter{}
+			router.HandleFunc("/users/{userID}/posts/%s/%s", func(w http.ResponseWriter, r *http.Request) {
+				bindErr = BindPath(r, dest)
+			})
+
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			if tt.expectedError != "" {
+				if bindErr == nil || !strings.Contains(bindErr.Error(), tt.expectedError) {
+					t.Fatalf("BindPath() error = %v; want %q", bindErr, tt.expectedError)
+				}
+				return
+			}
+
+			if bindErr != nil {
+				t.Fatalf("unexpected error: %v", bindErr)
+			}
+			if got := *dest.(*postPath); got != tt.expected {
+				t.Errorf("BindPath() bound %+v; want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBindPathEmptyValue(t *testing.T) {
+	type searchPath struct {
+		Query string `path:"q"`
+		Page  string `path:"page"`
+	}
+
+	var dest searchPath
+	var bindErr error
+	router := &customRouter{}
+	router.HandleFunc("/search?q={q:[a-z]*}", func(w http.ResponseWriter, r *http.Request) {
+		bindErr = BindPath(r, &dest)
+	})
+
+	req, err := http.NewRequest("GET", "/search?q=", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// "q" was captured empty so it binds, while "page" isn't declared at all
+	expected := "BindPath: field Page: parameter 'page' not captured"
+	if bindErr == nil || bindErr.Error() != expected {
+		t.Errorf("BindPath() error = %v; want %q", bindErr, expected)
+	}
+	if dest.Query != "" {
+		t.Errorf("expected an empty query, got %q", dest.Query)
+	}
+}
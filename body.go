@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// largest request body decodeJSON will read
+const maxJSONBodyBytes = 1 << 20
+
+// decodes the request body as a single JSON value into dest, i.e for POST/PUT handlers.
+// Bodies over maxJSONBodyBytes, unknown fields and trailing data are rejected with a descriptive error.
+func decodeJSON(r *http.Request, dest any) error {
+	if r.Body == nil || r.Body == http.NoBody {
+		return errors.New("request body is empty")
+	}
+
+	// read one byte past the limit so an oversized body can be told apart from one exactly at the limit
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxJSONBodyBytes+1))
+	if err != nil {
+		return fmt.Errorf("reading request body: %w", err)
+	}
+	if len(body) > maxJSONBodyBytes {
+		return fmt.Errorf("request body exceeds %d bytes", maxJSONBodyBytes)
+	}
+	if len(body) == 0 {
+		return errors.New("request body is empty")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dest); err != nil {
+		return fmt.Errorf("decoding request body: %w", err)
+	}
+
+	if decoder.More() {
+		return errors.New("decoding request body: unexpected data after JSON value")
+	}
+	return nil
+}
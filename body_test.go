@@ -0,0 +1,77 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	type payload struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+
+	tests := []struct {
+		name          string
+		body          string
+		expected      payload
+		expectedError string
+	}{
+		{
+			name:     "valid JSON",
+			body:     `{"name":"widget","count":3}`,
+			expected: payload{Name: "widget", Count: 3},
+		},
+		{
+			name:          "malformed JSON",
+			body:          `{"name":"widget",`,
+			expectedError: "decoding request body: unexpected EOF",
+		},
+		{
+			name:          "unknown field rejected",
+			body:          `{"name":"widget","colour":"red"}`,
+			expectedError: `decoding request body: json: unknown field "colour"`,
+		},
+		{
+			name:          "trailing data rejected",
+			body:          `{"name":"widget"} {"name":"gadget"}`,
+			expectedError: "decoding request body: unexpected data after JSON value",
+		},
+		{
+			name:          "empty body",
+			body:          "",
+			expectedError: "request body is empty",
+		},
+		{
+			name:          "oversized body",
+			body:          `{"name":"` + strings.Repeat("a", maxJSONBodyBytes) + `"}`,
+			expectedError: "request body exceeds 1048576 bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", "/widgets", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got payload
+			err = decodeJSON(req, &got)
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Fatalf("decodeJSON() error = %v; want %q", err, tt.expectedError)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("decodeJSON() decoded %+v; want %+v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// HandleFuncBodyMatch registers a POST route only matching requests whose JSON body has value at jsonPath,
+// a dot separated path into nested objects like "event.type", i.e to fan webhooks out by event. Numbers
+// and booleans are compared in their JSON form. Requests that don't match fall through to later routes,
+// and the handler can still read the whole body.
+func (r *customRouter) HandleFuncBodyMatch(pattern, jsonPath, value string, handler http.HandlerFunc) {
+	r.handle(pattern, handler, func(rt *route) {
+		rt.method = http.MethodPost
+		fields := strings.Split(jsonPath, ".")
+		rt.accepts = func(req *http.Request) bool {
+			body, err := peekBody(req, maxJSONBodyBytes)
+			if err != nil {
+				return false
+			}
+			field, ok := jsonField(body, fields)
+			return ok && field == value
+		}
+	})
+}
+
+// reads up to limit bytes of the request body and puts them back in front of the rest, so the body
+// can be read again from the start; it closes like the original
+func peekBody(req *http.Request, limit int64) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	peeked, err := io.ReadAll(io.LimitReader(req.Body, limit))
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(peeked), req.Body), req.Body}
+	return peeked, err
+}
+
+// returns the scalar at the path of object fields in the JSON document, reporting false if the document
+// doesn't parse, the path is missing or leads to an object, array or null
+func jsonField(doc []byte, fields []string) (string, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(doc))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return "", false
+	}
+
+	for _, field := range fields {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		if value, ok = object[field]; !ok {
+			return "", false
+		}
+	}
+
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case json.Number, bool:
+		return fmt.Sprint(value), true
+	}
+	return "", false
+}
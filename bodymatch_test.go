@@ -0,0 +1,78 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleFuncBodyMatch(t *testing.T) {
+	var handledBy, handledBody string
+	handler := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			handledBy, handledBody = name, string(body)
+		}
+	}
+
+	router := &customRouter{}
+	router.HandleFuncBodyMatch("/hooks/%s", "event.type", "push", handler("push"))
+	router.HandleFuncBodyMatch("/hooks/%s", "event.attempt", "2", handler("retry"))
+	router.HandleMethodFunc(http.MethodPost, "/hooks/%s", handler("fallback"))
+
+	tests := []struct {
+		name              string
+		body              string
+		expectedHandledBy string
+	}{
+		{name: "matching field", body: `{"event":{"type":"push","attempt":1},"repo":"x"}`, expectedHandledBy: "push"},
+		{name: "numeric field", body: `{"event":{"type":"issue","attempt":2}}`, expectedHandledBy: "retry"},
+		{name: "non-matching field", body: `{"event":{"type":"issue","attempt":1}}`, expectedHandledBy: "fallback"},
+		{name: "missing field", body: `{"event":"push"}`, expectedHandledBy: "fallback"},
+		{name: "not JSON", body: `event.type=push`, expectedHandledBy: "fallback"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handledBy, handledBody = "", ""
+			req, err := http.NewRequest(http.MethodPost, "/hooks/github", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+			}
+			if handledBy != tt.expectedHandledBy {
+				t.Errorf("request handled by %q; want %q", handledBy, tt.expectedHandledBy)
+			}
+			if handledBody != tt.body {
+				t.Errorf("handler read body %q; want the full body %q", handledBody, tt.body)
+			}
+		})
+	}
+
+	t.Run("no match", func(t *testing.T) {
+		router := &customRouter{}
+		router.HandleFuncBodyMatch("/hooks/%s", "event.type", "push", handler("push"))
+
+		req, err := http.NewRequest(http.MethodPost, "/hooks/github", strings.NewReader(`{"event":{"type":"issue"}}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected a 404 when no route's field matches, got %d", rr.Code)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitBreakerOptions configures HandleFuncCircuitBreaker
+type CircuitBreakerOptions struct {
+	// consecutive 5xx responses that open the breaker, defaults to 5
+	FailureThreshold int
+	// how long the breaker stays open before a trial request is let through, defaults to 30s
+	OpenDuration time.Duration
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// tracks consecutive failures of a route, see HandleFuncCircuitBreaker
+type circuitBreaker struct {
+	mu       sync.Mutex
+	opts     CircuitBreakerOptions
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+// reports whether a request may go through at now; once the open period has passed a single trial
+// request is allowed (half-open) and the rest are refused until its outcome is known
+func (b *circuitBreaker) allow(now time.Time) (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if wait := b.openedAt.Add(b.opts.OpenDuration).Sub(now); wait > 0 {
+			return false, wait
+		}
+		b.state = breakerHalfOpen
+		return true, 0
+	case breakerHalfOpen:
+		// a trial is in flight
+		return false, 0
+	default:
+		return true, 0
+	}
+}
+
+// records the outcome of a request let through by allow
+func (b *circuitBreaker) record(failed bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failed {
+		b.state, b.failures = breakerClosed, 0
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.opts.FailureThreshold {
+		b.state, b.openedAt = breakerOpen, now
+	}
+}
+
+// HandleFuncCircuitBreaker registers a route for a handler depending on a flaky downstream. After
+// FailureThreshold consecutive 5xx responses from handler the breaker opens and requests get a 503
+// without reaching it; once OpenDuration has passed one trial request goes through, closing the breaker
+// if it succeeds and reopening it if it fails.
+func (r *customRouter) HandleFuncCircuitBreaker(pattern string, handler http.HandlerFunc, opts CircuitBreakerOptions) {
+	if handler == nil {
+		panic(nilHandlerError(pattern))
+	}
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = 5
+	}
+	if opts.OpenDuration <= 0 {
+		opts.OpenDuration = 30 * time.Second
+	}
+	breaker := &circuitBreaker{opts: opts}
+
+	r.handle(pattern, func(w http.ResponseWriter, req *http.Request) {
+		allowed, wait := breaker.allow(r.now())
+		if !allowed {
+			if wait <= 0 {
+				// half-open: the outcome of the trial in flight decides when requests go through again
+				wait = r.retryAfter()
+			}
+			writeRetryAfter(w, wait)
+			r.writeError(w, http.StatusServiceUnavailable, "Service unavailable")
+			return
+		}
+
+		rec := newStatusRecorder(w)
+		// recorded even if handler panics, which counts as a failure, so a half-open trial can't leave
+		// the breaker stuck refusing requests
+		defer func() {
+			if err := recover(); err != nil {
+				breaker.record(true, r.now())
+				panic(err)
+			}
+			breaker.record(rec.Status() >= http.StatusInternalServerError, r.now())
+		}()
+		handler(rec, req)
+	})
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandleFuncCircuitBreaker(t *testing.T) {
+	now := time.Date(2024, time.May, 1, 9, 0, 0, 0, time.UTC)
+	downstreamUp := false
+	calls := 0
+
+	router := &customRouter{Now: func() time.Time { return now }}
+	router.HandleFuncCircuitBreaker("/quotes/%s", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if !downstreamUp {
+			http.Error(w, "downstream failed", http.StatusBadGateway)
+		}
+	}, CircuitBreakerOptions{FailureThreshold: 3, OpenDuration: 10 * time.Second})
+
+	serve := func() *httptest.ResponseRecorder {
+		t.Helper()
+		req, err := http.NewRequest("GET", "/quotes/q1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// failures below the threshold reach the handler
+	for i := 0; i < 3; i++ {
+		if rr := serve(); rr.Code != http.StatusBadGateway {
+			t.Fatalf("request %d: got status %d want %d", i+1, rr.Code, http.StatusBadGateway)
+		}
+	}
+
+	// the breaker is open: short-circuited without calling the handler
+	rr := serve()
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 while open, got %d", rr.Code)
+	}
+	if retryAfter := rr.Header().Get("Retry-After"); retryAfter != "10" {
+		t.Errorf("unexpected Retry-After: got %q want %q", retryAfter, "10")
+	}
+	if calls != 3 {
+		t.Errorf("expected the handler not to be called while open, got %d calls", calls)
+	}
+
+	// a failed trial after the open period reopens the breaker
+	now = now.Add(10 * time.Second)
+	if rr := serve(); rr.Code != http.StatusBadGateway {
+		t.Fatalf("expected the trial request to reach the handler, got %d", rr.Code)
+	}
+	if rr := serve(); rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 after a failed trial, got %d", rr.Code)
+	}
+
+	// a successful trial closes it again
+	now = now.Add(10 * time.Second)
+	downstreamUp = true
+	for i := 0; i < 3; i++ {
+		if rr := serve(); rr.Code != http.StatusOK {
+			t.Fatalf("request %d after recovery: got status %d want %d", i+1, rr.Code, http.StatusOK)
+		}
+	}
+	if calls != 7 {
+		t.Errorf("expected 7 handler calls, got %d", calls)
+	}
+}
+
+func TestCircuitBreakerHalfOpenAllowsOneTrial(t *testing.T) {
+	now := time.Date(2024, time.May, 1, 9, 0, 0, 0, time.UTC)
+	breaker := &circuitBreaker{opts: CircuitBreakerOptions{FailureThreshold: 1, OpenDuration: time.Second}}
+
+	breaker.record(true, now)
+	now = now.Add(time.Second)
+
+	if allowed, _ := breaker.allow(now); !allowed {
+		t.Fatal("expected a trial request once the open period passed")
+	}
+	if allowed, _ := breaker.allow(now); allowed {
+		t.Error("expected other requests refused while the trial is in flight")
+	}
+}
+
+func TestCircuitBreakerCountsPanics(t *testing.T) {
+	now := time.Date(2024, time.May, 1, 9, 0, 0, 0, time.UTC)
+	panicking := true
+
+	router := &customRouter{Now: func() time.Time { return now }}
+	router.HandleFuncCircuitBreaker("/quotes/%s", func(w http.ResponseWriter, r *http.Request) {
+		if panicking {
+			panic("downstream client blew up")
+		}
+	}, CircuitBreakerOptions{FailureThreshold: 1, OpenDuration: 10 * time.Second})
+
+	serve := func() (rr *httptest.ResponseRecorder, panicked bool) {
+		t.Helper()
+		req, err := http.NewRequest("GET", "/quotes/q1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr = httptest.NewRecorder()
+		defer func() {
+			panicked = recover() != nil
+		}()
+		router.ServeHTTP(rr, req)
+		return rr, false
+	}
+
+	// the panic is passed on and counts as a failure, opening the breaker
+	if _, panicked := serve(); !panicked {
+		t.Fatal("expected the handler's panic to propagate")
+	}
+	if rr, _ := serve(); rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 after a panic, got %d", rr.Code)
+	}
+
+	// a panicking trial reopens the breaker rather than leaving it half-open
+	now = now.Add(10 * time.Second)
+	if _, panicked := serve(); !panicked {
+		t.Fatal("expected the trial request to reach the handler")
+	}
+	now = now.Add(10 * time.Second)
+	panicking = false
+	if rr, _ := serve(); rr.Code != http.StatusOK {
+		t.Errorf("expected a successful trial after the open period, got %d", rr.Code)
+	}
+}
+
+func TestCircuitBreakerHalfOpenRetryAfter(t *testing.T) {
+	now := time.Date(2024, time.May, 1, 9, 0, 0, 0, time.UTC)
+	trialStarted := make(chan struct{})
+	finishTrial := make(chan struct{})
+	failing := true
+
+	router := &customRouter{Now: func() time.Time { return now }, RetryAfter: 5 * time.Second}
+	router.HandleFuncCircuitBreaker("/quotes/%s", func(w http.ResponseWriter, r *http.Request) {
+		if failing {
+			http.Error(w, "downstream failed", http.StatusBadGateway)
+			return
+		}
+		close(trialStarted)
+		<-finishTrial
+	}, CircuitBreakerOptions{FailureThreshold: 1, OpenDuration: 10 * time.Second})
+
+	serve := func() *httptest.ResponseRecorder {
+		req, err := http.NewRequest("GET", "/quotes/q1", nil)
+		if err != nil {
+			t.Error(err)
+			return nil
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	serve()
+	now = now.Add(10 * time.Second)
+	failing = false
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		serve()
+	}()
+	<-trialStarted
+
+	// refused while the trial is in flight, with the configured fallback delay
+	rr := serve()
+	close(finishTrial)
+	<-done
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 while the trial is in flight, got %d", rr.Code)
+	}
+	if retryAfter := rr.Header().Get("Retry-After"); retryAfter != "5" {
+		t.Errorf("unexpected Retry-After: got %q want %q", retryAfter, "5")
+	}
+}
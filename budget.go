@@ -0,0 +1,15 @@
+package main
+
+import (
+	"net/http"
+	"time"
+)
+
+// HandleFuncBudget registers a route whose requests carry a context deadline d after routing, so
+// handlers can observe the budget via r.Context() and give up on slow work. Unlike http.TimeoutHandler
+// nothing is cut off; a handler ignoring its context still runs to completion.
+func (r *customRouter) HandleFuncBudget(pattern string, handler http.HandlerFunc, d time.Duration) {
+	r.handle(pattern, handler, func(rt *route) {
+		rt.budget = d
+	})
+}
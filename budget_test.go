@@ -0,0 +1,50 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandleFuncBudget(t *testing.T) {
+	const budget = 2 * time.Second
+
+	var deadline time.Time
+	var hasDeadline bool
+	router := &customRouter{}
+	router.HandleFuncBudget("/reports/%s", func(w http.ResponseWriter, r *http.Request) {
+		deadline, hasDeadline = r.Context().Deadline()
+	}, budget)
+	router.HandleFunc("/plain/%s", func(w http.ResponseWriter, r *http.Request) {
+		_, hasDeadline = r.Context().Deadline()
+	})
+
+	t.Run("budgeted route", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/reports/r1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		start := time.Now()
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		if !hasDeadline {
+			t.Fatal("expected the request context to carry a deadline")
+		}
+		if remaining := deadline.Sub(start); remaining <= 0 || remaining > budget+100*time.Millisecond {
+			t.Errorf("expected a deadline roughly %v away, got %v", budget, remaining)
+		}
+	})
+
+	t.Run("route without a budget", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/plain/p1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		if hasDeadline {
+			t.Error("expected no deadline on a route without a budget")
+		}
+	})
+}
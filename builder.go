@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+)
+
+// RouterBuilder registers routes fluently, i.e NewRouter().GET("/foo/%s", h1).POST("/foo", h2).Build()
+type RouterBuilder struct {
+	specs []RouteSpec
+}
+
+// NewRouter starts a fluent route table, see RouterBuilder
+func NewRouter() *RouterBuilder {
+	return &RouterBuilder{}
+}
+
+// Handle adds a route for method; templates are only compiled by Build
+func (b *RouterBuilder) Handle(method, pattern string, handler http.HandlerFunc) *RouterBuilder {
+	b.specs = append(b.specs, RouteSpec{Method: method, Pattern: pattern, Handler: handler})
+	return b
+}
+
+// GET adds a GET route, see Handle
+func (b *RouterBuilder) GET(pattern string, handler http.HandlerFunc) *RouterBuilder {
+	return b.Handle(http.MethodGet, pattern, handler)
+}
+
+// POST adds a POST route, see Handle
+func (b *RouterBuilder) POST(pattern string, handler http.HandlerFunc) *RouterBuilder {
+	return b.Handle(http.MethodPost, pattern, handler)
+}
+
+// PUT adds a PUT route, see Handle
+func (b *RouterBuilder) PUT(pattern string, handler http.HandlerFunc) *RouterBuilder {
+	return b.Handle(http.MethodPut, pattern, handler)
+}
+
+// PATCH adds a PATCH route, see Handle
+func (b *RouterBuilder) PATCH(pattern string, handler http.HandlerFunc) *RouterBuilder {
+	return b.Handle(http.MethodPatch, pattern, handler)
+}
+
+// DELETE adds a DELETE route, see Handle
+func (b *RouterBuilder) DELETE(pattern string, handler http.HandlerFunc) *RouterBuilder {
+	return b.Handle(http.MethodDelete, pattern, handler)
+}
+
+// Build registers the routes in the order they were added on a new router, returning every route
+// that failed to compile joined into one error, in which case the router isn't returned
+func (b *RouterBuilder) Build() (*customRouter, error) {
+	router := &customRouter{}
+	if errs := router.HandleFuncAll(b.specs); len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return router, nil
+}
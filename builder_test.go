@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRouterBuilder(t *testing.T) {
+	respond := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, name, getParam(r, 1))
+		}
+	}
+
+	router, err := NewRouter().
+		GET("/orders/%s", respond("get")).
+		POST("/orders", respond("create")).
+		PUT("/orders/%s", respond("replace")).
+		DELETE("/orders/%s", respond("delete")).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method         string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{method: "GET", path: "/orders/o1", expectedStatus: http.StatusOK, expectedBody: "get o1\n"},
+		{method: "POST", path: "/orders", expectedStatus: http.StatusOK, expectedBody: "create \n"},
+		{method: "PUT", path: "/orders/o1", expectedStatus: http.StatusOK, expectedBody: "replace o1\n"},
+		{method: "DELETE", path: "/orders/o1", expectedStatus: http.StatusOK, expectedBody: "delete o1\n"},
+		{method: "PATCH", path: "/orders/o1", expectedStatus: http.StatusMethodNotAllowed, expectedBody: "Method not allowed\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestRouterBuilderSurfacesErrorsAtBuild(t *testing.T) {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	router, err := NewRouter().
+		GET("/orders/%s", noop).
+		GET("{@undefined}/items/%s", noop).
+		Build()
+
+	if err == nil {
+		t.Fatal("expected an error for the bad template")
+	}
+	if router != nil {
+		t.Error("expected no router alongside the error")
+	}
+	if !strings.Contains(err.Error(), "{@undefined}/items/%s") {
+		t.Errorf("expected the error to name the bad template, got %q", err)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import "strings"
+
+// returns the canonical form of a template so templates written slightly differently compare equal,
+// i.e "//foo//%s " and "/foo/%s" both give "/foo/%s": surrounding spaces are trimmed from the template
+// and each segment, repeated slashes collapsed and a leading slash added. A trailing slash is kept, as a
+// single one, since "/foo/%s/" matches different paths than "/foo/%s". A query part, i.e "?type=%s", is
+// kept with surrounding spaces trimmed.
+func canonicalizeTemplate(t string) string {
+	pathTemplate, queryTemplate, hasQuery := splitQueryTemplate(strings.TrimSpace(t))
+	versionPrefix := ""
+	if rest, ok := strings.CutPrefix(pathTemplate, optionalVersionPrefix); ok {
+		versionPrefix, pathTemplate = optionalVersionPrefix, rest
+	}
+
+	var segments []string
+	for _, segment := range strings.Split(pathTemplate, "/") {
+		if segment = strings.TrimSpace(segment); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+
+	canonical := versionPrefix + "/" + strings.Join(segments, "/")
+	if len(segments) > 0 && strings.HasSuffix(strings.TrimSpace(pathTemplate), "/") {
+		canonical += "/"
+	}
+	if hasQuery {
+		canonical += "?" + strings.TrimSpace(queryTemplate)
+	}
+	return canonical
+}
+
+// identifies what a template registers by the ServeMux prefix it's served under and its compiled
+// pattern; templates with the same registration shadow each other, while "/foo/%s" and "/foo/%s/" don't
+func templateRegistration(template, pattern string) string {
+	return getPathPrefix(template) + " " + pattern
+}
+
+// Routes returns the canonical template of every registered route in match order
+func (r *customRouter) Routes() []string {
+	routes := r.routeSnapshot()
+	templates := make([]string, 0, len(routes))
+	for _, rt := range routes {
+		templates = append(templates, canonicalizeTemplate(rt.template))
+	}
+	return templates
+}
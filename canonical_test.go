@@ -0,0 +1,95 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestCanonicalizeTemplate(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates []string
+		expected  string
+	}{
+		{
+			name:      "slashes and spaces",
+			templates: []string{"/foo/%s", "//foo//%s", "/foo/%s ", " /foo/ %s", "foo/%s", "/foo///%s"},
+			expected:  "/foo/%s",
+		},
+		{
+			name:      "trailing slash kept once",
+			templates: []string{"/foo/%s/", "//foo//%s/", "/foo///%s//", "/foo/%s/ "},
+			expected:  "/foo/%s/",
+		},
+		{
+			name:      "root",
+			templates: []string{"/", "//", "", " / "},
+			expected:  "/",
+		},
+		{
+			name:      "optional version prefix kept",
+			templates: []string{"(/v%d)?/resource/%s", "(/v%d)?//resource//%s"},
+			expected:  "(/v%d)?/resource/%s",
+		},
+		{
+			name:      "query part kept",
+			templates: []string{"/search?type=%s", "//search?type=%s "},
+			expected:  "/search?type=%s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, template := range tt.templates {
+				if result := canonicalizeTemplate(template); result != tt.expected {
+					t.Errorf("canonicalizeTemplate(%q) = %q; want %q", template, result, tt.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	router := &customRouter{}
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	router.HandleFunc("//users//%s/", noop)
+	router.HandleFunc("/health", noop)
+
+	expected := []string{"/users/%s/", "/health"}
+	if routes := router.Routes(); !slices.Equal(routes, expected) {
+		t.Errorf("Routes() = %q; want %q", routes, expected)
+	}
+}
+
+func TestDuplicateDetectionComparesRegistrations(t *testing.T) {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	t.Run("trailing slash variants are distinct", func(t *testing.T) {
+		if _, err := buildServeMux([]string{"/users/%s", "/users/%s/"}); err != nil {
+			t.Errorf("buildServeMux: unexpected error: %v", err)
+		}
+
+		router := &customRouter{}
+		router.HandleFunc("/users/%s", noop)
+		other := &customRouter{}
+		other.HandleFunc("/users/%s/", noop)
+		if err := router.Merge(other); err != nil {
+			t.Errorf("Merge: unexpected error: %v", err)
+		}
+	})
+
+	t.Run("same registration conflicts", func(t *testing.T) {
+		if _, err := buildServeMux([]string{"/users/%s", "/users/%s"}); err == nil {
+			t.Error("buildServeMux: expected an error for a repeated template")
+		}
+
+		router := &customRouter{}
+		router.HandleFunc("/users/%s", noop)
+		other := &customRouter{}
+		other.HandleFunc("/users/%s", noop)
+		if err := router.Merge(other); err == nil {
+			t.Error("Merge: expected a conflict for a repeated template")
+		}
+	})
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// HandleFuncCatchAll sets the handler for requests no route matches, with the full path as param 1,
+// i.e "/some/unknown/page" for getParam(r, 1). It's only tried after every route regardless of when it's
+// registered, serves any method, and takes precedence over AddNotFoundFallback handlers.
+// Setting it again replaces the previous catch-all; like route handlers it panics if handler is nil.
+func (r *customRouter) HandleFuncCatchAll(handler http.HandlerFunc) {
+	if handler == nil {
+		panic(errors.New("nil handler for the catch-all"))
+	}
+	r.catchAll = handler
+}
+
+// serves req with the catch-all, passing its path as the only param
+func (r *customRouter) serveCatchAll(w http.ResponseWriter, req *http.Request) {
+	ctx := context.WithValue(req.Context(), paramKey(1), req.URL.Path)
+	ctx = context.WithValue(ctx, paramNamesKey{}, []string{""})
+	ctx = context.WithValue(ctx, methodKey{}, req.Method)
+	r.wrapHandler(r.catchAll)(w, req.WithContext(ctx))
+}
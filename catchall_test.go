@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleFuncCatchAll(t *testing.T) {
+	router := &customRouter{}
+	// registered first, it must still only run once every specific route has been tried
+	router.HandleFuncCatchAll(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "catch-all %s %d", getParam(r, 1), len(getAllParamsOrdered(r)))
+	})
+	router.HandleFunc("/users/%s", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "user %s", getParam(r, 1))
+	})
+	router.HandleMethodFunc(http.MethodPost, "/orders", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "order")
+	})
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "specific route wins", method: "GET", path: "/users/u1", expectedStatus: http.StatusOK, expectedBody: "user u1"},
+		{name: "unmatched path", method: "GET", path: "/some/unknown/page", expectedStatus: http.StatusOK, expectedBody: "catch-all /some/unknown/page 1"},
+		{name: "root", method: "GET", path: "/", expectedStatus: http.StatusOK, expectedBody: "catch-all / 1"},
+		{name: "other methods", method: "DELETE", path: "/users/u1/extra", expectedStatus: http.StatusOK, expectedBody: "catch-all /users/u1/extra 1"},
+		{name: "path matched with the wrong method", method: "GET", path: "/orders", expectedStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+			if tt.expectedBody != "" && rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+)
+
+// HandleFuncClasses registers a route where classes[i] is the regex class of the template's i-th '%s'
+// param, i.e []string{"[0-9]+", ""} for "/orders/%s/items/%s" takes a numeric order and the default
+// alphanumeric item. An empty entry keeps the default class. It panics if there are more classes than
+// '%s' params or a class doesn't compile or has capturing groups, like other invalid registrations.
+func (r *customRouter) HandleFuncClasses(pattern string, classes []string, handler http.HandlerFunc) {
+	if positional := r.positionalParamCount(pattern); len(classes) > positional {
+		panic(fmt.Errorf("invalid template '%s': %d classes given for %d '%%s' params", pattern, len(classes), positional))
+	}
+	for i, class := range classes {
+		if class == "" {
+			continue
+		}
+		// a class mustn't add groups of its own or param positions would shift
+		re, err := regexp.Compile(class)
+		if err != nil {
+			panic(fmt.Errorf("invalid template '%s': class %d: %w", pattern, i+1, err))
+		}
+		if re.NumSubexp() > 0 {
+			panic(fmt.Errorf("invalid template '%s': class %d '%s' has capturing groups", pattern, i+1, class))
+		}
+	}
+
+	rt, err := r.compileRouteClasses(pattern, handler, classes)
+	if err != nil {
+		panic(err)
+	}
+	r.addRoute(rt)
+}
+
+// counts the '%s' params classes apply to: those of the template's path part once fragments are
+// expanded, so a query like "?type=%s" or a fragment's own '%s' are counted right. Templates whose
+// fragments don't expand count as written; registering them fails anyway.
+func (r *customRouter) positionalParamCount(pattern string) int {
+	if expanded, err := r.expandFragments(pattern); err == nil {
+		pattern = expanded
+	}
+	pathTemplate, _, _ := splitQueryTemplate(pattern)
+
+	count := 0
+	walkTemplate(pathTemplate, templateOptions{delimiter: r.Delimiter}, func(string) {}, func(p ParamInfo, _ string) {
+		if p.Verb == 's' {
+			count++
+		}
+	})
+	return count
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleFuncClasses(t *testing.T) {
+	router := &customRouter{}
+	router.HandleFuncClasses("/orders/%s/items/%s", []string{"[0-9]+", ""}, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s", getParam(r, 1), getParam(r, 2))
+	})
+	router.HandleFuncClasses("/tags/%s/%s", []string{"", "[a-z-]+"}, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s", getParam(r, 1), getParam(r, 2))
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "numeric then alphanumeric", path: "/orders/42/items/abc9", expectedStatus: http.StatusOK, expectedBody: "42 abc9"},
+		{name: "first param not numeric", path: "/orders/a42/items/abc9", expectedStatus: http.StatusNotFound},
+		{name: "second param keeps the default class", path: "/orders/42/items/abc-9", expectedStatus: http.StatusNotFound},
+		{name: "override of the second param", path: "/tags/go1/new-release", expectedStatus: http.StatusOK, expectedBody: "go1 new-release"},
+		{name: "second param outside its class", path: "/tags/go1/release2", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+			if tt.expectedStatus == http.StatusOK && rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestHandleFuncClassesInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		classes []string
+	}{
+		{name: "more classes than params", classes: []string{"[0-9]+", "", "[a-z]+"}},
+		{name: "class doesn't compile", classes: []string{"[0-9"}},
+		{name: "capturing group", classes: []string{"([0-9]+)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic registering classes %q", tt.classes)
+				}
+			}()
+			router := &customRouter{}
+			router.HandleFuncClasses("/orders/%s/items/%s", tt.classes, func(w http.ResponseWriter, r *http.Request) {})
+		})
+	}
+}
+
+func TestHandleFuncClassesCountsPathParams(t *testing.T) {
+	t.Run("query params aren't positional", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected a panic for a class given for a query param")
+			}
+		}()
+		router := &customRouter{}
+		router.HandleFuncClasses("/orders/%s?status=%s", []string{"[0-9]+", "[a-z]+"}, func(w http.ResponseWriter, r *http.Request) {})
+	})
+
+	t.Run("fragments are expanded first", func(t *testing.T) {
+		router := &customRouter{}
+		if err := router.DefineFragment("tenant", "/tenants/%s"); err != nil {
+			t.Fatal(err)
+		}
+		router.HandleFuncClasses("{@tenant}/orders/%s", []string{"[a-z]+", "[0-9]+"}, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "%s %s", getParam(r, 1), getParam(r, 2))
+		})
+
+		req, err := http.NewRequest("GET", "/tenants/acme/orders/42", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK || rr.Body.String() != "acme 42" {
+			t.Errorf("unexpected response: got %d %q want %d %q", rr.Code, rr.Body.String(), http.StatusOK, "acme 42")
+		}
+	})
+
+	t.Run("placeholders with the default class aren't positional", func(t *testing.T) {
+		for _, template := range []string{"/orders/{id}/items/%s", "/orders/{id:[a-zA-Z0-9]+}/items/%s"} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("expected a panic for a class given for a placeholder in '%s'", template)
+					}
+				}()
+				router := &customRouter{}
+				router.HandleFuncClasses(template, []string{"[0-9]+", ""}, func(w http.ResponseWriter, r *http.Request) {})
+			}()
+		}
+	})
+}
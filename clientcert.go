@@ -0,0 +1,49 @@
+package main
+
+import (
+	"crypto/x509"
+	"net/http"
+	"slices"
+)
+
+// HandleFuncRequireClientCert registers a route only serving requests that presented a TLS client
+// certificate the server verified, i.e for mTLS protected admin endpoints; others get a 403. A certificate
+// that was presented but not verified, as with tls.RequestClientCert, is rejected too. When filters are
+// given the verified leaf certificate must pass at least one of them, see certCommonName and certOrganization.
+func (r *customRouter) HandleFuncRequireClientCert(pattern string, handler http.HandlerFunc, filters ...func(*x509.Certificate) bool) {
+	if handler == nil {
+		panic(nilHandlerError(pattern))
+	}
+	r.handle(pattern, func(w http.ResponseWriter, req *http.Request) {
+		// only verified chains can be trusted, PeerCertificates may hold a self-signed certificate
+		if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 || len(req.TLS.VerifiedChains[0]) == 0 {
+			r.logger().Infof("Rejected %s %s without a verified client certificate", req.Method, req.URL.Path)
+			r.writeError(w, http.StatusForbidden, "Forbidden: client certificate required")
+			return
+		}
+
+		leaf := req.TLS.VerifiedChains[0][0]
+		if len(filters) > 0 && !slices.ContainsFunc(filters, func(allow func(*x509.Certificate) bool) bool { return allow(leaf) }) {
+			r.logger().Infof("Rejected %s %s with client certificate '%s'", req.Method, req.URL.Path, leaf.Subject)
+			r.writeError(w, http.StatusForbidden, "Forbidden: client certificate not allowed")
+			return
+		}
+		handler(w, req)
+	})
+}
+
+// client certificate filter accepting any of the given subject common names
+func certCommonName(names ...string) func(*x509.Certificate) bool {
+	return func(cert *x509.Certificate) bool {
+		return slices.Contains(names, cert.Subject.CommonName)
+	}
+}
+
+// client certificate filter accepting subjects in any of the given organizations
+func certOrganization(orgs ...string) func(*x509.Certificate) bool {
+	return func(cert *x509.Certificate) bool {
+		return slices.ContainsFunc(cert.Subject.Organization, func(org string) bool {
+			return slices.Contains(orgs, org)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleFuncRequireClientCert(t *testing.T) {
+	router := &customRouter{}
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	router.HandleFuncRequireClientCert("/admin/%s", noop)
+	router.HandleFuncRequireClientCert("/ops/%s", noop, certCommonName("deployer"), certOrganization("Platform"))
+
+	certFor := func(cn string, orgs ...string) *tls.ConnectionState {
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: cn, Organization: orgs}}
+		return &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+			VerifiedChains:   [][]*x509.Certificate{{cert}},
+		}
+	}
+	// presented but not verified, i.e a self-signed cert on a server using tls.RequestClientCert
+	unverifiedCertFor := func(cn string) *tls.ConnectionState {
+		return &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: cn}}}}
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		tls            *tls.ConnectionState
+		expectedStatus int
+	}{
+		{name: "plain HTTP", path: "/admin/stats", expectedStatus: http.StatusForbidden},
+		{name: "TLS without a client cert", path: "/admin/stats", tls: &tls.ConnectionState{}, expectedStatus: http.StatusForbidden},
+		{name: "client cert", path: "/admin/stats", tls: certFor("anyone"), expectedStatus: http.StatusOK},
+		{name: "allowed common name", path: "/ops/deploy", tls: certFor("deployer"), expectedStatus: http.StatusOK},
+		{name: "allowed organization", path: "/ops/deploy", tls: certFor("alice", "Platform"), expectedStatus: http.StatusOK},
+		{name: "unverified client cert", path: "/admin/stats", tls: unverifiedCertFor("anyone"), expectedStatus: http.StatusForbidden},
+		{name: "unverified forged common name", path: "/ops/deploy", tls: unverifiedCertFor("deployer"), expectedStatus: http.StatusForbidden},
+		{name: "filtered out", path: "/ops/deploy", tls: certFor("mallory", "Elsewhere"), expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.TLS = tt.tls
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+		})
+	}
+}
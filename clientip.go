@@ -0,0 +1,50 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// returns the IP of the client that sent the request. When the connection comes from one of the
+// TrustedProxies, 'X-Forwarded-For' is walked from the nearest hop back and the first address that
+// isn't a trusted proxy is the client. Reports false if no valid address is found, including when a
+// trusted proxy's chain has a malformed hop, i.e "203.0.113.9:4444" or "unknown", before reaching an
+// untrusted one: falling back to the proxy's own address would pass it off as the client.
+func (r *customRouter) clientIP(req *http.Request) (netip.Addr, bool) {
+	host := req.RemoteAddr
+	if h, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		host = h
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	addr = addr.Unmap()
+	if !r.isTrustedProxy(addr) {
+		return addr, true
+	}
+
+	forwarded := strings.Split(strings.Join(req.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(forwarded[i]))
+		if err != nil {
+			return netip.Addr{}, false
+		}
+		if addr = hop.Unmap(); !r.isTrustedProxy(addr) {
+			return addr, true
+		}
+	}
+	// every hop is a trusted proxy, the client is unknown
+	return netip.Addr{}, false
+}
+
+func (r *customRouter) isTrustedProxy(addr netip.Addr) bool {
+	for _, prefix := range r.TrustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
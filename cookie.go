@@ -0,0 +1,22 @@
+package main
+
+import "net/http"
+
+// HandleFuncCookie registers a route only serving requests carrying the named cookie with the given value,
+// i.e for A/B testing. It's preferred over a registration of the same template without a cookie, which
+// serves every other request.
+func (r *customRouter) HandleFuncCookie(pattern, cookieName, cookieValue string, handler http.HandlerFunc) {
+	r.handle(pattern, handler, func(rt *route) {
+		rt.cookie = &http.Cookie{Name: cookieName, Value: cookieValue}
+	})
+}
+
+// reports whether the request carries a cookie with want's name and value
+func hasCookie(req *http.Request, want *http.Cookie) bool {
+	for _, c := range req.Cookies() {
+		if c.Name == want.Name && c.Value == want.Value {
+			return true
+		}
+	}
+	return false
+}
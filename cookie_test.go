@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleFuncCookie(t *testing.T) {
+	variant := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, name, getParam(r, 1))
+		}
+	}
+
+	router := &customRouter{}
+	router.HandleFunc("/checkout/%s", variant("control"))
+	router.HandleFuncCookie("/checkout/%s", "experiment", "b", variant("treatment"))
+	// no cookie-less registration to fall back on
+	router.HandleFuncCookie("/beta/%s", "beta", "1", variant("beta"))
+
+	tests := []struct {
+		name           string
+		path           string
+		cookie         *http.Cookie
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "matching cookie",
+			path:           "/checkout/c1",
+			cookie:         &http.Cookie{Name: "experiment", Value: "b"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "treatment c1\n",
+		},
+		{
+			name:           "absent cookie falls back",
+			path:           "/checkout/c1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "control c1\n",
+		},
+		{
+			name:           "other cookie value falls back",
+			path:           "/checkout/c1",
+			cookie:         &http.Cookie{Name: "experiment", Value: "a"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "control c1\n",
+		},
+		{
+			name:           "cookie route without fallback",
+			path:           "/beta/x1",
+			cookie:         &http.Cookie{Name: "beta", Value: "1"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "beta x1\n",
+		},
+		{
+			name:           "absent cookie without fallback",
+			path:           "/beta/x1",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.cookie != nil {
+				req.AddCookie(tt.cookie)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestVariantsOfExplicitGetRoute(t *testing.T) {
+	router := &customRouter{}
+	router.HandleMethodFunc(http.MethodGet, "/home", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "default")
+	})
+	router.HandleFuncCookie("/home", "exp", "b", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "variant-b")
+	})
+	router.HandleFuncLang("/home", "fr", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "bonjour")
+	})
+
+	tests := []struct {
+		name           string
+		cookie         *http.Cookie
+		acceptLanguage string
+		expectedBody   string
+	}{
+		{name: "no preference", expectedBody: "default"},
+		{name: "cookie variant", cookie: &http.Cookie{Name: "exp", Value: "b"}, expectedBody: "variant-b"},
+		{name: "language variant", acceptLanguage: "fr", expectedBody: "bonjour"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/home", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.cookie != nil {
+				req.AddCookie(tt.cookie)
+			}
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestVariantsMarkedAtRegistration(t *testing.T) {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	router := &customRouter{}
+	router.HandleFunc("/checkout/%s", noop)
+	router.HandleFuncCookie("/checkout/%s", "experiment", "b", noop)
+	router.HandleFuncLang("/about", "fr", noop)
+	router.HandleFunc("/orders/%s", noop)
+
+	expected := []bool{true, true, false, false}
+	for i, rt := range router.routeSnapshot() {
+		if got := rt.hasVariants.Load(); got != expected[i] {
+			t.Errorf("route '%s': expected hasVariants %v, got %v", rt.template, expected[i], got)
+		}
+	}
+}
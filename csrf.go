@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	csrfCookieName = "csrf_token"
+	csrfHeaderName = "X-CSRF-Token"
+
+	// how long a token from the in-memory store stays valid after being issued
+	csrfTokenTTL = 12 * time.Hour
+)
+
+// context key for the CSRF token issued to the request, see csrfToken
+type csrfTokenKey struct{}
+
+// CSRFTokenStore issues CSRF tokens and reports whether a presented token is one it issued,
+// so tokens can be shared between instances, i.e backed by a cache
+type CSRFTokenStore interface {
+	NewToken() (string, error)
+	Valid(token string) bool
+}
+
+// default CSRFTokenStore remembering issued tokens in memory until they expire after ttl
+type memoryTokenStore struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	now       func() time.Time
+	tokens    map[string]time.Time // issue time of each token
+	lastSweep time.Time
+}
+
+func newMemoryTokenStore(ttl time.Duration, now func() time.Time) *memoryTokenStore {
+	return &memoryTokenStore{ttl: ttl, now: now, tokens: make(map[string]time.Time), lastSweep: now()}
+}
+
+func (s *memoryTokenStore) NewToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.sweep(now)
+	s.tokens[token] = now
+	return token, nil
+}
+
+func (s *memoryTokenStore) Valid(token string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issued, ok := s.tokens[token]
+	return ok && s.now().Sub(issued) < s.ttl
+}
+
+// drops expired tokens so the store doesn't grow without bound; it runs at most once per ttl so issuing
+// stays cheap, meaning expired tokens linger for up to another ttl but are already rejected by Valid.
+// s.mu must be held.
+func (s *memoryTokenStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < s.ttl {
+		return
+	}
+	for token, issued := range s.tokens {
+		if now.Sub(issued) >= s.ttl {
+			delete(s.tokens, token)
+		}
+	}
+	s.lastSweep = now
+}
+
+// CSRFProtect adds middleware issuing a CSRF token on safe requests (GET, HEAD, OPTIONS) as a cookie,
+// also available to handlers via csrfToken, and requiring state-changing requests to echo it in the
+// 'X-CSRF-Token' header. Requests with a missing, mismatched or unknown token get a 403.
+// Tokens come from CSRFTokenStore, or if it isn't set an in-memory store whose tokens expire 12 hours
+// after being issued (timed with the router's Now), after which a safe request issues a fresh one.
+func (r *customRouter) CSRFProtect() {
+	if r.CSRFTokenStore == nil {
+		r.CSRFTokenStore = newMemoryTokenStore(csrfTokenTTL, r.now)
+	}
+	store := r.CSRFTokenStore
+
+	r.use(func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, req *http.Request) {
+			var cookieToken string
+			if cookie, err := req.Cookie(csrfCookieName); err == nil && store.Valid(cookie.Value) {
+				cookieToken = cookie.Value
+			}
+
+			switch req.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				if cookieToken == "" {
+					token, err := store.NewToken()
+					if err != nil {
+						r.logger().Errorf("Error issuing CSRF token: %v", err)
+						r.writeError(w, http.StatusInternalServerError, "Internal server error")
+						return
+					}
+					cookieToken = token
+					http.SetCookie(w, &http.Cookie{
+						Name:     csrfCookieName,
+						Value:    token,
+						Path:     "/",
+						HttpOnly: true,
+						SameSite: http.SameSiteStrictMode,
+					})
+				}
+			default:
+				headerToken := req.Header.Get(csrfHeaderName)
+				if cookieToken == "" || subtle.ConstantTimeCompare([]byte(headerToken), []byte(cookieToken)) != 1 {
+					r.logger().Infof("Rejected %s %s with a missing or invalid CSRF token", req.Method, req.URL.Path)
+					r.writeError(w, http.StatusForbidden, "Forbidden: invalid CSRF token")
+					return
+				}
+			}
+
+			next(w, req.WithContext(context.WithValue(req.Context(), csrfTokenKey{}, cookieToken)))
+		}
+	})
+}
+
+// returns the CSRF token for the request, i.e to embed in a form, or "" if CSRFProtect isn't enabled
+func csrfToken(r *http.Request) string {
+	token, _ := r.Context().Value(csrfTokenKey{}).(string)
+	return token
+}
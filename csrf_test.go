@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCSRFProtect(t *testing.T) {
+	router := &customRouter{}
+	router.CSRFProtect()
+	router.HandleFunc("/form/%s", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, csrfToken(r) != "")
+	})
+	router.HandleMethodFunc(http.MethodPost, "/form/%s", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "saved", getParam(r, 1))
+	})
+
+	// a GET issues the token as a cookie
+	req, err := http.NewRequest("GET", "/form/f1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Body.String() != "true\n" {
+		t.Errorf("expected the token in the handler's context, got body %q", rr.Body.String())
+	}
+	cookies := rr.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != csrfCookieName {
+		t.Fatalf("expected a %s cookie, got %v", csrfCookieName, cookies)
+	}
+	token := cookies[0].Value
+
+	tests := []struct {
+		name           string
+		cookie         string
+		header         string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "valid token",
+			cookie:         token,
+			header:         token,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "saved f1\n",
+		},
+		{
+			name:           "missing header",
+			cookie:         token,
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   "Forbidden: invalid CSRF token\n",
+		},
+		{
+			name:           "mismatched header",
+			cookie:         token,
+			header:         "not-the-token",
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   "Forbidden: invalid CSRF token\n",
+		},
+		{
+			name:           "token the store never issued",
+			cookie:         "forged",
+			header:         "forged",
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   "Forbidden: invalid CSRF token\n",
+		},
+		{
+			name:           "no cookie",
+			header:         token,
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   "Forbidden: invalid CSRF token\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", "/form/f1", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: csrfCookieName, Value: tt.cookie})
+			}
+			if tt.header != "" {
+				req.Header.Set(csrfHeaderName, tt.header)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestCSRFProtectExpiredToken(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	router := &customRouter{Now: func() time.Time { return now }}
+	router.CSRFProtect()
+	router.HandleFunc("/form/%s", func(w http.ResponseWriter, r *http.Request) {})
+	router.HandleMethodFunc(http.MethodPost, "/form/%s", func(w http.ResponseWriter, r *http.Request) {})
+
+	req, err := http.NewRequest("GET", "/form/f1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	cookies := rr.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("expected a %s cookie, got %v", csrfCookieName, cookies)
+	}
+	token := cookies[0].Value
+
+	post := func() int {
+		req, err := http.NewRequest("POST", "/form/f1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.AddCookie(&http.Cookie{Name: csrfCookieName, Value: token})
+		req.Header.Set(csrfHeaderName, token)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	now = now.Add(csrfTokenTTL - time.Second)
+	if status := post(); status != http.StatusOK {
+		t.Errorf("token just before expiry: got status %v want %v", status, http.StatusOK)
+	}
+
+	now = now.Add(time.Second)
+	if status := post(); status != http.StatusForbidden {
+		t.Errorf("expired token: got status %v want %v", status, http.StatusForbidden)
+	}
+
+	// issuing a new token sweeps the expired one from the store
+	store := router.CSRFTokenStore.(*memoryTokenStore)
+	if _, err := store.NewToken(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.tokens[token]; ok || len(store.tokens) != 1 {
+		t.Errorf("expected the expired token to be swept, store holds %d tokens", len(store.tokens))
+	}
+}
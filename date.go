@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// layout of the dates matched by a '%t' placeholder
+const dateParamLayout = "2006-01-02"
+
+// regex class of a '%t' placeholder
+const dateParamClass = `[0-9]{4}-[0-9]{2}-[0-9]{2}`
+
+// rejects '%t' params that have the right shape but aren't calendar dates, i.e "2023-02-30"
+func validateDateParam(value string) error {
+	if _, err := time.Parse(dateParamLayout, value); err != nil {
+		return fmt.Errorf("invalid date '%s'", value)
+	}
+	return nil
+}
+
+// returns the 1-indexed path parameter parsed as a time in layout, i.e dateParamLayout for a '%t' param
+func getParamTime(r *http.Request, index int, layout string) (time.Time, error) {
+	value, ok := getParamOK(r, index)
+	if !ok {
+		return time.Time{}, fmt.Errorf("parameter %d: not captured", index)
+	}
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parameter %d: %w", index, err)
+	}
+	return t, nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDateParam(t *testing.T) {
+	router := &customRouter{}
+	router.HandleFunc("/reports/%t", func(w http.ResponseWriter, r *http.Request) {
+		day, err := getParamTime(r, 1, dateParamLayout)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintln(w, day.Weekday())
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "valid date",
+			path:           "/reports/2023-01-15",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Sunday\n",
+		},
+		{
+			name:           "invalid calendar date",
+			path:           "/reports/2023-02-30",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "parameter 1: invalid date '2023-02-30'\n",
+		},
+		{
+			name:           "wrong format",
+			path:           "/reports/15-01-2023",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestGetParamTimeLayout(t *testing.T) {
+	router := &customRouter{}
+	var parseErr error
+	router.HandleFunc("/months/{month:[0-9]{4}-[0-9]{2}}", func(w http.ResponseWriter, r *http.Request) {
+		_, parseErr = getParamTime(r, 1, "2006-01-02")
+	})
+
+	req, err := http.NewRequest("GET", "/months/2023-01", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if parseErr == nil {
+		t.Error("expected an error parsing a value that doesn't fit the layout")
+	}
+}
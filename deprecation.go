@@ -0,0 +1,20 @@
+package main
+
+import (
+	"net/http"
+	"time"
+)
+
+// HandleFuncDeprecated registers a route that keeps working but marks every response as deprecated,
+// setting the 'Deprecation' header and a 'Sunset' header with the date the route will be removed
+func (r *customRouter) HandleFuncDeprecated(pattern string, handler http.HandlerFunc, sunset time.Time) {
+	if handler == nil {
+		panic(nilHandlerError(pattern))
+	}
+	sunsetStr := sunset.UTC().Format(http.TimeFormat)
+	r.handle(pattern, func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Sunset", sunsetStr)
+		handler(w, req)
+	})
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandleFuncDeprecated(t *testing.T) {
+	router := &customRouter{}
+	routeTemplateStr := "/api/v2/%s"
+	sunset := time.Date(2025, time.December, 31, 23, 59, 59, 0, time.UTC)
+	router.HandleFuncDeprecated(routeTemplateStr, newDynamicPathHandler(routeTemplateStr), sunset)
+
+	req, err := http.NewRequest("GET", "/api/v2/item1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	if got := rr.Header().Get("Deprecation"); got != "true" {
+		t.Errorf("unexpected Deprecation header: got %q want %q", got, "true")
+	}
+
+	expectedSunset := "Wed, 31 Dec 2025 23:59:59 GMT"
+	if got := rr.Header().Get("Sunset"); got != expectedSunset {
+		t.Errorf("unexpected Sunset header: got %q want %q", got, expectedSunset)
+	}
+
+	parsed, err := http.ParseTime(rr.Header().Get("Sunset"))
+	if err != nil {
+		t.Fatalf("Sunset header is not a valid HTTP date: %v", err)
+	}
+	if !parsed.Equal(sunset) {
+		t.Errorf("Sunset header parsed to %v, want %v", parsed, sunset)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"maps"
+	"slices"
+)
+
+// DiffRoutes compares the route tables of two routers by template, i.e so CI can flag a deploy that
+// removes routes. Templates only registered on newRouter are added and those only on oldRouter are removed;
+// a template on both is changed when the set of methods it serves differs. Handlers can't be compared so
+// a route counts as long as it has one. Each list is sorted.
+func DiffRoutes(oldRouter, newRouter *customRouter) (added, removed, changed []string) {
+	oldMethods := routeMethodSets(oldRouter)
+	newMethods := routeMethodSets(newRouter)
+
+	for template, methods := range newMethods {
+		previous, ok := oldMethods[template]
+		switch {
+		case !ok:
+			added = append(added, template)
+		case !maps.Equal(previous, methods):
+			changed = append(changed, template)
+		}
+	}
+	for template := range oldMethods {
+		if _, ok := newMethods[template]; !ok {
+			removed = append(removed, template)
+		}
+	}
+
+	slices.Sort(added)
+	slices.Sort(removed)
+	slices.Sort(changed)
+	return added, removed, changed
+}
+
+// returns the methods served by each template registered with a handler
+func routeMethodSets(r *customRouter) map[string]map[string]bool {
+	sets := make(map[string]map[string]bool)
+	if r == nil {
+		return sets
+	}
+	for _, rt := range r.routeSnapshot() {
+		if rt.handler == nil {
+			continue
+		}
+		if sets[rt.template] == nil {
+			sets[rt.template] = make(map[string]bool)
+		}
+		sets[rt.template][rt.effectiveMethod()] = true
+	}
+	return sets
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestDiffRoutes(t *testing.T) {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	oldRouter := &customRouter{}
+	oldRouter.HandleFunc("/users/%s", noop)
+	oldRouter.HandleFunc("/legacy/%s", noop)
+	oldRouter.HandleFunc("/orders/%s", noop)
+	oldRouter.HandleMethodFunc(http.MethodDelete, "/orders/%s", noop)
+
+	newRouter := &customRouter{}
+	newRouter.HandleFunc("/users/%s", noop)
+	newRouter.HandleFunc("/orders/%s", noop)
+	newRouter.HandleMethodFunc(http.MethodPut, "/orders/%s", noop)
+	newRouter.HandleFunc("/invoices/%s", noop)
+
+	added, removed, changed := DiffRoutes(oldRouter, newRouter)
+
+	tests := []struct {
+		name     string
+		result   []string
+		expected []string
+	}{
+		{name: "added", result: added, expected: []string{"/invoices/%s"}},
+		{name: "removed", result: removed, expected: []string{"/legacy/%s"}},
+		{name: "changed method set", result: changed, expected: []string{"/orders/%s"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !slices.Equal(tt.result, tt.expected) {
+				t.Errorf("got %q want %q", tt.result, tt.expected)
+			}
+		})
+	}
+
+	t.Run("identical routers", func(t *testing.T) {
+		added, removed, changed := DiffRoutes(newRouter, newRouter)
+		if len(added)+len(removed)+len(changed) != 0 {
+			t.Errorf("expected no differences, got %q %q %q", added, removed, changed)
+		}
+	})
+}
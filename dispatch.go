@@ -0,0 +1,43 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+)
+
+// returns a handler for routes capturing a variable number of segments, i.e a HandlePrefix catch-all,
+// calling the handler registered for the number captured; 404 when there's none. The remainder of a
+// prefix route counts as one param per non-empty segment, so "/files/" with "a/b/c" captures 3.
+func dispatchByParamCount(handlers map[int]http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		handler, ok := handlers[capturedSegmentCount(r)]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// returns how many params the router captured for the request, splitting a prefix route's remainder
+// into its segments
+func capturedSegmentCount(r *http.Request) int {
+	names, _ := r.Context().Value(paramNamesKey{}).([]string)
+	count := 0
+	for i := range names {
+		if _, ok := getParamOK(r, i+1); ok {
+			count++
+		}
+	}
+
+	if remainder, ok := r.Context().Value(remainderKey{}).(string); ok {
+		// the remainder was counted as a single param above
+		count--
+		for _, segment := range strings.Split(remainder, "/") {
+			if segment != "" {
+				count++
+			}
+		}
+	}
+	return count
+}
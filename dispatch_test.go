@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDispatchByParamCount(t *testing.T) {
+	router := &customRouter{}
+	router.HandlePrefix("/files/", dispatchByParamCount(map[int]http.HandlerFunc{
+		1: func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, "file", remainderPath(r))
+		},
+		3: func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, "nested file", remainderPath(r))
+		},
+	}))
+	router.HandleFunc("/pairs/%s/%s", dispatchByParamCount(map[int]http.HandlerFunc{
+		2: func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, "pair", getParam(r, 1), getParam(r, 2))
+		},
+	}))
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "one param",
+			path:           "/files/readme",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "file readme\n",
+		},
+		{
+			name:           "three params",
+			path:           "/files/docs/v1/readme",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "nested file docs/v1/readme\n",
+		},
+		{
+			name:           "no handler for the count",
+			path:           "/files/docs/readme",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+		{
+			name:           "fixed template",
+			path:           "/pairs/a/b",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "pair a b\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
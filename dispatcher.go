@@ -0,0 +1,39 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+)
+
+// http.Handler registered on a ServeMux prefix, dispatching to the first template sharing the prefix
+// whose pattern matches the path
+type prefixDispatcher struct {
+	patterns []*regexp.Regexp
+	handlers []http.HandlerFunc
+}
+
+func (d *prefixDispatcher) add(pattern *regexp.Regexp, handler http.HandlerFunc) {
+	d.patterns = append(d.patterns, pattern)
+	d.handlers = append(d.handlers, handler)
+}
+
+func (d *prefixDispatcher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	for i, pattern := range d.patterns {
+		if pattern.MatchString(r.URL.Path) {
+			d.handlers[i](w, r)
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+// returns the dispatcher already registered on the mux for exactly this prefix, if any
+func registeredDispatcher(mux *http.ServeMux, prefix string) (*prefixDispatcher, bool) {
+	handler, pattern := mux.Handler(&http.Request{Method: http.MethodGet, URL: &url.URL{Path: prefix}})
+	if pattern != prefix {
+		return nil, false
+	}
+	dispatcher, ok := handler.(*prefixDispatcher)
+	return dispatcher, ok
+}
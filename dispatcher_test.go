@@ -0,0 +1,195 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegisterRouteTemplatesSharedPrefix(t *testing.T) {
+	mux := http.NewServeMux()
+
+	// both templates derive the "/api/v3/" prefix, which would panic if registered twice
+	registerRouteTemplates(mux, []string{
+		"/api/v3/%s/%s",
+		"/api/v3/%s/other/%s",
+		"/foo/bar/%s/baz/%s/qux",
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "first template sharing the prefix",
+			path:           "/api/v3/id1/id2",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Parameter 1: id1\nParameter 2: id2\n",
+		},
+		{
+			name:           "second template sharing the prefix",
+			path:           "/api/v3/id1/other/id2",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Parameter 1: id1\nParameter 2: id2\n",
+		},
+		{
+			name:           "prefix matches but no template does",
+			path:           "/api/v3/id1/id2/id3/id4",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+		{
+			name:           "unrelated prefix",
+			path:           "/foo/bar/a/baz/b/qux",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Parameter 1: a\nParameter 2: b\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestBuildServeMux(t *testing.T) {
+	mux, err := buildServeMux([]string{
+		"/api/v3/%s/%s",
+		"/api/v3/%s/other/%s",
+		"/users/%s",
+		"/health",
+		"/orders/%d",
+		"/reports/%t",
+		"/geo/{coords:2}",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "shared prefix",
+			path:           "/api/v3/id1/other/id2",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Parameter 1: id1\nParameter 2: id2\n",
+		},
+		{
+			name:           "single param",
+			path:           "/users/u1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Parameter 1: u1\n",
+		},
+		{
+			name:           "static template",
+			path:           "/health",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "No parameters captured.\n",
+		},
+		{
+			name:           "numeric param",
+			path:           "/orders/42",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Parameter 1: 42\n",
+		},
+		{
+			name:           "date param",
+			path:           "/reports/2023-01-15",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Parameter 1: 2023-01-15\n",
+		},
+		{
+			name:           "segment count param",
+			path:           "/geo/45.0/-93.0",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Parameter 1: 45.0/-93.0\n",
+		},
+		{
+			name:           "no template matches",
+			path:           "/users/u1/extra",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestBuildServeMuxErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates []string
+	}{
+		{name: "duplicate template", templates: []string{"/users/%s", "/users/%s"}},
+		{name: "empty template", templates: []string{""}},
+		{name: "pattern the mux rejects", templates: []string{"/files/{bad"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := buildServeMux(tt.templates); err == nil {
+				t.Errorf("expected an error for %q", tt.templates)
+			}
+		})
+	}
+}
+
+func TestBuildServeMuxTrailingSlashVariants(t *testing.T) {
+	// the same canonical template with and without a trailing slash registers distinct patterns
+	mux, err := buildServeMux([]string{"/foo/%s", "/foo/%s/"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, path := range []string{"/foo/abc", "/foo/abc/"} {
+		req, err := http.NewRequest("GET", path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("%s: handler returned wrong status code: got %v want %v", path, status, http.StatusOK)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DryRun returns a human readable summary of every registered route (method, template and compiled
+// pattern) in match order, for validating a route table before deploying without starting a server.
+// Templates are compiled as GET routes would be without registering them and listed after the
+// registered routes, followed by the compile errors of those that are invalid.
+func (r *customRouter) DryRun(templates ...string) string {
+	routes := r.routeSnapshot()
+	var compileErrors []error
+	for _, template := range templates {
+		rt, err := r.compileRoute(template, func(w http.ResponseWriter, req *http.Request) {})
+		if err != nil {
+			compileErrors = append(compileErrors, err)
+			continue
+		}
+		routes = append(routes[:len(routes):len(routes)], rt)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d route(s) would be registered:\n", len(routes))
+	for i, rt := range routes {
+		fmt.Fprintf(&sb, "  %d. %-6s %s -> %s", i+1, rt.effectiveMethod(), rt.template, rt.pattern)
+		if rt.host != nil {
+			fmt.Fprintf(&sb, " (host %s)", rt.host)
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(compileErrors) > 0 {
+		fmt.Fprintf(&sb, "%d template(s) failed to compile:\n", len(compileErrors))
+		for _, err := range compileErrors {
+			fmt.Fprintf(&sb, "  - %v\n", err)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	router := &customRouter{}
+	templates := []string{
+		"/api/v3/%s/%s",
+		"/api/v3/%s/%s/version",
+		"/foo/bar/%s/baz/%s/qux",
+	}
+	router.addTemplateRoutes(templates)
+	router.HandleMethodFunc(http.MethodDelete, "/items/{itemID}", func(w http.ResponseWriter, r *http.Request) {})
+
+	summary := router.DryRun()
+
+	if !strings.HasPrefix(summary, "4 route(s) would be registered:\n") {
+		t.Errorf("summary has an unexpected header:\n%s", summary)
+	}
+
+	for _, template := range templates {
+		expectedLine := "GET    " + template + " -> " + makeRegexPatternStr(template)
+		if !strings.Contains(summary, expectedLine) {
+			t.Errorf("summary is missing %q:\n%s", expectedLine, summary)
+		}
+	}
+
+	if !strings.Contains(summary, "4. DELETE /items/{itemID} -> ^/items/(?P<itemID>[a-zA-Z0-9]+)$") {
+		t.Errorf("summary is missing the DELETE route:\n%s", summary)
+	}
+}
+
+func TestDryRunCompileErrors(t *testing.T) {
+	router := &customRouter{}
+	router.HandleFunc("/api/v3/%s/%s", func(w http.ResponseWriter, r *http.Request) {})
+
+	summary := router.DryRun("/users/{userID}", "/drafts/{@slug", "/posts/{@slug}")
+
+	if !strings.HasPrefix(summary, "2 route(s) would be registered:\n") {
+		t.Errorf("summary has an unexpected header:\n%s", summary)
+	}
+	if !strings.Contains(summary, "2. GET    /users/{userID} -> ^/users/(?P<userID>[a-zA-Z0-9]+)$") {
+		t.Errorf("summary is missing the pending template:\n%s", summary)
+	}
+	if !strings.Contains(summary, "2 template(s) failed to compile:\n") {
+		t.Errorf("summary is missing the compile errors:\n%s", summary)
+	}
+	for _, template := range []string{"/drafts/{@slug", "/posts/{@slug}"} {
+		if !strings.Contains(summary, template) {
+			t.Errorf("summary is missing the error for %q:\n%s", template, summary)
+		}
+	}
+
+	// dry runs never register anything
+	if len(router.Routes()) != 1 {
+		t.Errorf("expected the router's routes untouched, got %v", router.Routes())
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRejectEarlyData(t *testing.T) {
+	router := &customRouter{RejectEarlyData: true}
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	router.HandleFunc("/orders/%s", noop)
+	router.HandleMethodFunc(http.MethodPost, "/orders/%s", noop)
+
+	tests := []struct {
+		name           string
+		method         string
+		earlyData      bool
+		expectedStatus int
+	}{
+		{name: "early data POST", method: "POST", earlyData: true, expectedStatus: http.StatusTooEarly},
+		{name: "early data GET", method: "GET", earlyData: true, expectedStatus: http.StatusOK},
+		{name: "POST without early data", method: "POST", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, "/orders/o1", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.earlyData {
+				req.Header.Set("Early-Data", "1")
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		router.RejectEarlyData = false
+		req, err := http.NewRequest("POST", "/orders/o1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Early-Data", "1")
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+	})
+}
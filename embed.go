@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+)
+
+// HandleEmbed registers a route serving files from fsys, i.e an embed.FS of static assets, with the
+// template's last param selecting the file, i.e "/assets/{file:[a-zA-Z0-9._/-]+}". Names that could
+// escape fsys, like "../secret", get a 400; missing files and directories a 404. The Content-Type comes
+// from the file's extension, falling back to sniffing its content.
+func (r *customRouter) HandleEmbed(pattern string, fsys fs.FS) {
+	r.handle(pattern, func(w http.ResponseWriter, req *http.Request) {
+		params := getAllParamsOrdered(req)
+		if len(params) == 0 {
+			r.writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+
+		name := params[len(params)-1].Value
+		if !fs.ValidPath(name) {
+			r.logger().Infof("Rejected embedded file path %q for %s", name, req.URL.Path)
+			r.writeError(w, http.StatusBadRequest, "Bad Request: invalid file path")
+			return
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) && !isDirectory(fsys, name) {
+				r.logger().Errorf("Failed to read embedded file %q: %v", name, err)
+				r.writeError(w, http.StatusInternalServerError, "Internal Server Error")
+				return
+			}
+			r.writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+
+		contentType := mime.TypeByExtension(path.Ext(name))
+		if contentType == "" {
+			contentType = http.DetectContentType(data)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Write(data)
+	})
+}
+
+func isDirectory(fsys fs.FS, name string) bool {
+	info, err := fs.Stat(fsys, name)
+	return err == nil && info.IsDir()
+}
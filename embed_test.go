@@ -0,0 +1,73 @@
+package main
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+//go:embed testdata/embed
+var embeddedAssets embed.FS
+
+func TestHandleEmbed(t *testing.T) {
+	assets, err := fs.Sub(embeddedAssets, "testdata/embed")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := &customRouter{}
+	router.HandleEmbed("/assets/{file:.+}", assets)
+
+	tests := []struct {
+		name                string
+		path                string
+		expectedStatus      int
+		expectedBody        string
+		expectedContentType string
+	}{
+		{
+			name:                "text file",
+			path:                "/assets/hello.txt",
+			expectedStatus:      http.StatusOK,
+			expectedBody:        "hello from an embedded file\n",
+			expectedContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:                "css file",
+			path:                "/assets/app.css",
+			expectedStatus:      http.StatusOK,
+			expectedBody:        "body { color: red; }\n",
+			expectedContentType: "text/css; charset=utf-8",
+		},
+		{name: "missing file", path: "/assets/missing.txt", expectedStatus: http.StatusNotFound},
+		{name: "traversal", path: "/assets/../embed_test.go", expectedStatus: http.StatusBadRequest},
+		{name: "encoded traversal", path: "/assets/..%2F..%2Fgo.mod", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+			if contentType := rr.Header().Get("Content-Type"); contentType != tt.expectedContentType {
+				t.Errorf("unexpected Content-Type: got %q want %q", contentType, tt.expectedContentType)
+			}
+		})
+	}
+}
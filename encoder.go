@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+)
+
+// Encoder renders response data in some wire format, i.e JSON, protobuf or msgpack
+type Encoder interface {
+	Encode(w io.Writer, data any) error
+	ContentType() string
+}
+
+// a captured path param as rendered by newEncodedPathHandler
+type encodedParam struct {
+	Index int    `json:"index"`
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value"`
+}
+
+// Encoder writing data as JSON
+type jsonEncoder struct{}
+
+func (jsonEncoder) Encode(w io.Writer, data any) error { return json.NewEncoder(w).Encode(data) }
+func (jsonEncoder) ContentType() string                { return "application/json" }
+
+// Encoder writing params one per line like newPathRegexHandler, and anything else with fmt
+type plainTextEncoder struct{}
+
+func (plainTextEncoder) Encode(w io.Writer, data any) error {
+	params, ok := data.([]encodedParam)
+	if !ok {
+		_, err := fmt.Fprintln(w, data)
+		return err
+	}
+
+	if len(params) == 0 {
+		_, err := fmt.Fprintln(w, "No parameters captured.")
+		return err
+	}
+	for _, p := range params {
+		if _, err := fmt.Fprintf(w, "Parameter %d: %s\n", p.Index, p.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (plainTextEncoder) ContentType() string { return "text/plain; charset=utf-8" }
+
+// creates an http.HandlerFunc like newPathRegexHandler but rendering the captured params as a
+// []encodedParam with enc, so services can pick the wire format
+func newEncodedPathHandler(template string, enc Encoder) http.HandlerFunc {
+	pathPattern := regexp.MustCompile(makeRegexPatternStr(template))
+	names := pathPattern.SubexpNames()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		matches := pathPattern.FindStringSubmatch(r.URL.Path)
+		if matches == nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		params := make([]encodedParam, 0, len(matches)-1)
+		for i, match := range matches[1:] {
+			params = append(params, encodedParam{Index: i + 1, Name: names[i+1], Value: match})
+		}
+
+		w.Header().Set("Content-Type", enc.ContentType())
+		if err := enc.Encode(w, params); err != nil {
+			// the status line has likely been sent, all we can do is log
+			log.Printf("Error encoding params for path '%s': %v", r.URL.Path, err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Encoder standing in for a binary format like msgpack
+type stubEncoder struct{}
+
+func (stubEncoder) Encode(w io.Writer, data any) error {
+	for _, p := range data.([]encodedParam) {
+		fmt.Fprintf(w, "%d=%s;", p.Index, p.Value)
+	}
+	return nil
+}
+
+func (stubEncoder) ContentType() string { return "application/x-stub" }
+
+func TestEncodedPathHandler(t *testing.T) {
+	tests := []struct {
+		name                string
+		encoder             Encoder
+		path                string
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "JSON",
+			encoder:             jsonEncoder{},
+			path:                "/users/u1/posts/p2",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json",
+			expectedBody:        `[{"index":1,"name":"user","value":"u1"},{"index":2,"value":"p2"}]` + "\n",
+		},
+		{
+			name:                "plain text",
+			encoder:             plainTextEncoder{},
+			path:                "/users/u1/posts/p2",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "Parameter 1: u1\nParameter 2: p2\n",
+		},
+		{
+			name:                "custom encoder",
+			encoder:             stubEncoder{},
+			path:                "/users/u1/posts/p2",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/x-stub",
+			expectedBody:        "1=u1;2=p2;",
+		},
+		{
+			name:                "no match",
+			encoder:             jsonEncoder{},
+			path:                "/users/u1",
+			expectedStatus:      http.StatusNotFound,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "404 page not found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newEncodedPathHandler("/users/{user}/posts/%s", tt.encoder)
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+			if contentType := rr.Header().Get("Content-Type"); contentType != tt.expectedContentType {
+				t.Errorf("unexpected Content-Type: got %q want %q", contentType, tt.expectedContentType)
+			}
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
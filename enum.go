@@ -0,0 +1,40 @@
+package main
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// valid options of a case-insensitive enum, plain words so lowercasing gives the canonical value
+var enumOptionRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// converts "pdf|CSV" to a class matching either option in any case, reporting false for invalid options
+func caseInsensitiveEnumClass(options string) (string, bool) {
+	values := strings.Split(options, "|")
+	for i, value := range values {
+		if !enumOptionRegex.MatchString(value) {
+			return "", false
+		}
+		values[i] = strings.ToLower(value)
+	}
+	return "(?i:" + strings.Join(values, "|") + ")", true
+}
+
+// returns the path params with case-insensitive enum values lowercased to their canonical form,
+// copying rather than modifying params
+func (rt *route) canonicalParams(params []string) []string {
+	cloned := false
+	for i, param := range rt.params {
+		if !param.CaseInsensitive || i >= len(params) {
+			continue
+		}
+		if lower := strings.ToLower(params[i]); lower != params[i] {
+			if !cloned {
+				params, cloned = slices.Clone(params), true
+			}
+			params[i] = lower
+		}
+	}
+	return params
+}
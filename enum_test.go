@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCaseInsensitiveEnumParam(t *testing.T) {
+	router := &customRouter{}
+	router.HandleFunc("/reports/%s/export/{format:pdf|csv|html,ci}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s as %s (%s)\n", getParam(r, 1), getParam(r, 2), getParamByName(r, "format"))
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "canonical value",
+			path:           "/reports/Q3/export/pdf",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Q3 as pdf (pdf)\n",
+		},
+		{
+			name:           "uppercase stored lowercased",
+			path:           "/reports/Q3/export/PDF",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Q3 as pdf (pdf)\n",
+		},
+		{
+			name:           "mixed case stored lowercased",
+			path:           "/reports/Q3/export/Html",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Q3 as html (html)\n",
+		},
+		{
+			name:           "value outside the enum",
+			path:           "/reports/Q3/export/docx",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// body of a JSON error response, see customRouter.JSONErrors
+type errorEnvelope struct {
+	Error errorDetail `json:"error"`
+}
+
+type errorDetail struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// writes an error response the same way http.Error does, or as a JSON envelope when JSONErrors is set
+func (r *customRouter) writeError(w http.ResponseWriter, status int, message string) {
+	if !r.JSONErrors {
+		http.Error(w, message, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorEnvelope{Error: errorDetail{Code: status, Message: message}})
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestJSONErrors(t *testing.T) {
+	router := &customRouter{JSONErrors: true}
+	router.addTemplateRoutes([]string{"/api/v3/%s/%s"})
+
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name:            "404",
+			method:          http.MethodGet,
+			path:            "/missing",
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "404 page not found",
+		},
+		{
+			name:            "405",
+			method:          http.MethodPost,
+			path:            "/api/v3/id1/id2",
+			expectedStatus:  http.StatusMethodNotAllowed,
+			expectedMessage: "Method not allowed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			if contentType := rr.Header().Get("Content-Type"); contentType != "application/json" {
+				t.Errorf("unexpected Content-Type: got %q want %q", contentType, "application/json")
+			}
+
+			var envelope errorEnvelope
+			if err := json.Unmarshal(rr.Body.Bytes(), &envelope); err != nil {
+				t.Fatalf("response is not a JSON envelope: %v (body %q)", err, rr.Body.String())
+			}
+
+			expected := errorDetail{Code: tt.expectedStatus, Message: tt.expectedMessage}
+			if envelope.Error != expected {
+				t.Errorf("unexpected error envelope: got %+v want %+v", envelope.Error, expected)
+			}
+		})
+	}
+}
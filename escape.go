@@ -0,0 +1,18 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// reports an error if the request's escaped path has an invalid percent-encoding like "%zz" or "%2".
+// net/http already rejects these when parsing a request, but a URL built or rewritten by other code,
+// i.e a proxy setting RawPath, can carry one that would otherwise just fail to match any route.
+func checkPathEscapes(req *http.Request) error {
+	if req.URL.RawPath == "" {
+		// Path is the only form and is already decoded
+		return nil
+	}
+	_, err := url.PathUnescape(req.URL.RawPath)
+	return err
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestMalformedPercentEncoding(t *testing.T) {
+	router := &customRouter{}
+	router.HandleFunc("/user/{name:[^/]+}/profile", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, getParamByName(r, "name"))
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		rawPath        string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "invalid hex digits",
+			path:           "/user/%zz/profile",
+			rawPath:        "/user/%zz/profile",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Bad request: malformed percent-encoding in path\n",
+		},
+		{
+			name:           "truncated escape",
+			path:           "/user/%2/profile",
+			rawPath:        "/user/%2/profile",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Bad request: malformed percent-encoding in path\n",
+		},
+		{
+			name:           "valid escape",
+			path:           "/user/a b/profile",
+			rawPath:        "/user/a%20b/profile",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "a b",
+		},
+		{
+			name:           "decoded percent sign",
+			path:           "/user/%zz/profile",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "%zz",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &http.Request{Method: "GET", URL: &url.URL{Path: tt.path, RawPath: tt.rawPath}, Header: http.Header{}}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+
+	t.Run("parsed request", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/user/a%20b/profile", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK || rr.Body.String() != "a b" {
+			t.Errorf("got %d %q; want 200 %q", rr.Code, rr.Body.String(), "a b")
+		}
+	})
+}
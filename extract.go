@@ -0,0 +1,87 @@
+package main
+
+import (
+	"net/url"
+	"regexp"
+)
+
+// compiles a route template, i.e "/foo/bar/%s/baz/%s/qux", to its anchored regex
+func compileTemplate(template string) (*regexp.Regexp, error) {
+	return regexp.Compile(makeRegexPatternStr(template))
+}
+
+// ExtractParams matches path against the template and returns the captured params in order,
+// reporting false if the path doesn't match. This allows using templates outside of an HTTP handler.
+func ExtractParams(template, path string) ([]string, bool) {
+	params, err := ExtractParamsErr(template, path)
+	return params, err == nil
+}
+
+// ExtractParamsErr is ExtractParams returning a *MatchError describing why the path doesn't match,
+// i.e which segment failed, for precise diagnostics
+func ExtractParamsErr(template, path string) ([]string, error) {
+	pattern, err := compileTemplate(template)
+	if err != nil {
+		return nil, &MatchError{Template: template, Path: path, Kind: MatchErrorInvalidTemplate, Err: err}
+	}
+
+	matches := pattern.FindStringSubmatch(path)
+	if matches == nil {
+		return nil, diagnoseMismatch(template, path)
+	}
+	// first match is the full match, ignore it
+	return matches[1:], nil
+}
+
+// ExtractParamsFromURL is ExtractParams for a parsed URL, i.e from a log line or queue message.
+// Only u.Path is matched; the query and fragment are ignored.
+func ExtractParamsFromURL(template string, u *url.URL) ([]string, bool) {
+	if u == nil {
+		return nil, false
+	}
+	return ExtractParams(template, u.Path)
+}
+
+// ParamSpan is a captured param along with its byte offsets in the matched path, path[Start:End] == Value
+type ParamSpan struct {
+	Value      string
+	Start, End int
+}
+
+// ExtractParamsWithIndices is ExtractParams but also reports where in the path each param sits,
+// i.e for rewriting a single param in place
+func ExtractParamsWithIndices(template, path string) ([]ParamSpan, bool) {
+	pattern, err := compileTemplate(template)
+	if err != nil {
+		return nil, false
+	}
+
+	indices := pattern.FindStringSubmatchIndex(path)
+	if indices == nil {
+		return nil, false
+	}
+
+	// the first pair is the full match, ignore it
+	spans := make([]ParamSpan, 0, pattern.NumSubexp())
+	for i := 2; i < len(indices); i += 2 {
+		start, end := indices[i], indices[i+1]
+		if start < 0 {
+			// group didn't participate in the match
+			spans = append(spans, ParamSpan{Start: -1, End: -1})
+			continue
+		}
+		spans = append(spans, ParamSpan{Value: path[start:end], Start: start, End: end})
+	}
+	return spans, true
+}
+
+// ClassifyPath returns the index of the first template matching path along with its params,
+// i.e for switching on the kind of path in a classifier. Index is -1 if no template matches.
+func ClassifyPath(path string, templates []string) (int, []string, bool) {
+	for i, template := range templates {
+		if params, ok := ExtractParams(template, path); ok {
+			return i, params, true
+		}
+	}
+	return -1, nil, false
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestExtractParams(t *testing.T) {
+	tests := []struct {
+		name           string
+		template       string
+		path           string
+		expectedParams []string
+		expectedOK     bool
+	}{
+		{
+			name:           "two params",
+			template:       "/foo/bar/%s/baz/%s/qux",
+			path:           "/foo/bar/alpha123/baz/beta456/qux",
+			expectedParams: []string{"alpha123", "beta456"},
+			expectedOK:     true,
+		},
+		{
+			name:           "no params",
+			template:       "/static/path",
+			path:           "/static/path",
+			expectedParams: []string{},
+			expectedOK:     true,
+		},
+		{
+			name:       "non-matching path",
+			template:   "/foo/bar/%s/baz/%s/qux",
+			path:       "/foo/bar/alpha123/baz/qux",
+			expectedOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, ok := ExtractParams(tt.template, tt.path)
+			if ok != tt.expectedOK {
+				t.Fatalf("ExtractParams(%q, %q) ok = %v; want %v", tt.template, tt.path, ok, tt.expectedOK)
+			}
+			if ok && !reflect.DeepEqual(params, tt.expectedParams) {
+				t.Errorf("ExtractParams(%q, %q) = %q; want %q", tt.template, tt.path, params, tt.expectedParams)
+			}
+		})
+	}
+}
+
+func TestExtractParamsFromURL(t *testing.T) {
+	tests := []struct {
+		name           string
+		rawURL         string
+		expectedParams []string
+		expectedOK     bool
+	}{
+		{
+			name:           "query and fragment are ignored",
+			rawURL:         "https://example.com/foo/bar/alpha123/baz/beta456/qux?page=2&sort=asc#section",
+			expectedParams: []string{"alpha123", "beta456"},
+			expectedOK:     true,
+		},
+		{
+			name:           "relative URL",
+			rawURL:         "/foo/bar/one/baz/two/qux?x=1",
+			expectedParams: []string{"one", "two"},
+			expectedOK:     true,
+		},
+		{
+			name:       "non-matching path",
+			rawURL:     "https://example.com/foo/bar/alpha123/qux?baz=beta456",
+			expectedOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.rawURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			params, ok := ExtractParamsFromURL("/foo/bar/%s/baz/%s/qux", u)
+			if ok != tt.expectedOK {
+				t.Fatalf("ExtractParamsFromURL(%q) ok = %v; want %v", tt.rawURL, ok, tt.expectedOK)
+			}
+			if ok && !reflect.DeepEqual(params, tt.expectedParams) {
+				t.Errorf("ExtractParamsFromURL(%q) = %q; want %q", tt.rawURL, params, tt.expectedParams)
+			}
+		})
+	}
+
+	if _, ok := ExtractParamsFromURL("/foo/%s", nil); ok {
+		t.Error("expected a nil URL not to match")
+	}
+}
+
+func TestExtractParamsWithIndices(t *testing.T) {
+	path := "/foo/bar/alpha123/baz/beta456/qux"
+	spans, ok := ExtractParamsWithIndices("/foo/bar/%s/baz/%s/qux", path)
+	if !ok {
+		t.Fatalf("expected %q to match", path)
+	}
+
+	expected := []ParamSpan{
+		{Value: "alpha123", Start: 9, End: 17},
+		{Value: "beta456", Start: 22, End: 29},
+	}
+	if !reflect.DeepEqual(spans, expected) {
+		t.Fatalf("ExtractParamsWithIndices() = %+v; want %+v", spans, expected)
+	}
+
+	for _, span := range spans {
+		if path[span.Start:span.End] != span.Value {
+			t.Errorf("path[%d:%d] = %q; want %q", span.Start, span.End, path[span.Start:span.End], span.Value)
+		}
+	}
+
+	if _, ok := ExtractParamsWithIndices("/foo/bar/%s/baz/%s/qux", "/foo/bar/alpha123/qux"); ok {
+		t.Error("expected a non-matching path not to match")
+	}
+}
+
+func TestClassifyPath(t *testing.T) {
+	templates := []string{
+		"/users/%s",
+		"/users/%s/posts/%s",
+		"/users/%s/posts/%s/comments",
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedIndex  int
+		expectedParams []string
+		expectedOK     bool
+	}{
+		{
+			name:           "matches the second template",
+			path:           "/users/u1/posts/p2",
+			expectedIndex:  1,
+			expectedParams: []string{"u1", "p2"},
+			expectedOK:     true,
+		},
+		{
+			name:           "matches the first template",
+			path:           "/users/u1",
+			expectedIndex:  0,
+			expectedParams: []string{"u1"},
+			expectedOK:     true,
+		},
+		{
+			name:          "no template matches",
+			path:          "/groups/g1",
+			expectedIndex: -1,
+			expectedOK:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index, params, ok := ClassifyPath(tt.path, templates)
+			if index != tt.expectedIndex || ok != tt.expectedOK || !reflect.DeepEqual(params, tt.expectedParams) {
+				t.Errorf("ClassifyPath(%q) = %d, %q, %v; want %d, %q, %v",
+					tt.path, index, params, ok, tt.expectedIndex, tt.expectedParams, tt.expectedOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"regexp/syntax"
+	"slices"
+)
+
+// matches path against the route's pattern like regexp.FindStringSubmatch, also reporting whether each
+// group participated in the match so optional groups can be told apart from empty ones
+func (rt *route) match(path string) ([]string, []bool) {
+	return rt.matchInto(path, &submatches{})
+}
+
+// like match but filling buf, i.e one from acquireSubmatches, instead of allocating the results; they're
+// only valid until buf is reused or released
+func (rt *route) matchInto(path string, buf *submatches) ([]string, []bool) {
+	indices := rt.pattern.FindStringSubmatchIndex(path)
+	if indices == nil {
+		return nil, nil
+	}
+	buf.fill(path, indices)
+	return buf.values, buf.captured
+}
+
+// rewrites a template's regex so the text outside its capturing groups, the template's static parts,
+// matches ASCII letters of either case. Unlike (?i) nothing beyond ASCII is folded, so "/ſtatic" doesn't
+// match "/static" nor the Kelvin sign "/Kit" match "/kit"; params are capturing groups, so their classes
+// still see the path as sent. Regexes that don't parse are returned as is for regexp.Compile to report.
+func foldStaticASCII(regexStr string) string {
+	re, err := syntax.Parse(regexStr, syntax.Perl)
+	if err != nil {
+		return regexStr
+	}
+	return foldASCII(re).String()
+}
+
+func foldASCII(re *syntax.Regexp) *syntax.Regexp {
+	switch re.Op {
+	case syntax.OpCapture:
+		return re
+	case syntax.OpLiteral:
+		if re.Flags&syntax.FoldCase != 0 {
+			// already folded by an explicit (?i) in the template
+			return re
+		}
+		return foldLiteralASCII(re)
+	case syntax.OpCharClass:
+		re.Rune = foldClassASCII(re.Rune)
+	}
+	for i, sub := range re.Sub {
+		re.Sub[i] = foldASCII(sub)
+	}
+	return re
+}
+
+// replaces each ASCII letter of a literal with a class of both its cases, i.e "ab1" with "[Aa][Bb]1"
+func foldLiteralASCII(re *syntax.Regexp) *syntax.Regexp {
+	concat := &syntax.Regexp{Op: syntax.OpConcat, Flags: re.Flags}
+	var text []rune
+	flush := func() {
+		if len(text) > 0 {
+			concat.Sub = append(concat.Sub, &syntax.Regexp{Op: syntax.OpLiteral, Flags: re.Flags, Rune: text})
+			text = nil
+		}
+	}
+	for _, c := range re.Rune {
+		lower, upper, ok := asciiCases(c)
+		if !ok {
+			text = append(text, c)
+			continue
+		}
+		flush()
+		concat.Sub = append(concat.Sub, &syntax.Regexp{Op: syntax.OpCharClass, Flags: re.Flags, Rune: []rune{upper, upper, lower, lower}})
+	}
+	flush()
+	if len(concat.Sub) == 1 {
+		return concat.Sub[0]
+	}
+	return concat
+}
+
+// adds the other ASCII case of every letter in a class given as sorted [lo, hi] range pairs
+func foldClassASCII(ranges []rune) []rune {
+	folded := slices.Clone(ranges)
+	for i := 0; i < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		for _, letters := range [][2]rune{{'a', 'z'}, {'A', 'Z'}} {
+			from, to := max(lo, letters[0]), min(hi, letters[1])
+			if from > to {
+				continue
+			}
+			// the same letters in the other case are 32 code points away
+			shift := rune('a' - 'A')
+			if letters[0] == 'a' {
+				shift = -shift
+			}
+			folded = append(folded, from+shift, to+shift)
+		}
+	}
+	return mergeRanges(folded)
+}
+
+// sorts range pairs and merges overlapping or adjacent ones, the form syntax.Regexp expects of a class
+func mergeRanges(ranges []rune) []rune {
+	pairs := make([][2]rune, 0, len(ranges)/2)
+	for i := 0; i < len(ranges); i += 2 {
+		pairs = append(pairs, [2]rune{ranges[i], ranges[i+1]})
+	}
+	slices.SortFunc(pairs, func(a, b [2]rune) int { return int(a[0] - b[0]) })
+
+	merged := make([]rune, 0, len(ranges))
+	for _, p := range pairs {
+		if n := len(merged); n > 0 && p[0] <= merged[n-1]+1 {
+			merged[n-1] = max(merged[n-1], p[1])
+			continue
+		}
+		merged = append(merged, p[0], p[1])
+	}
+	return merged
+}
+
+// returns both cases of an ASCII letter, reporting false for anything else
+func asciiCases(c rune) (rune, rune, bool) {
+	switch {
+	case 'a' <= c && c <= 'z':
+		return c, c - ('a' - 'A'), true
+	case 'A' <= c && c <= 'Z':
+		return c + ('a' - 'A'), c, true
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLowercaseStaticSegments(t *testing.T) {
+	router := &customRouter{LowercaseStaticSegments: true}
+	router.HandleFunc("/FOO/bar/%s", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "captured %s\n", getParam(r, 1))
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "lowercase path",
+			path:           "/foo/bar/x",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "captured x\n",
+		},
+		{
+			name:           "mixed case static segments",
+			path:           "/Foo/BAR/x",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "captured x\n",
+		},
+		{
+			name:           "param captured exactly",
+			path:           "/foo/bar/MixedCase42",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "captured MixedCase42\n",
+		},
+		{
+			name:           "different static segment",
+			path:           "/foo/baz/x",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestLowercaseStaticSegmentsDisabled(t *testing.T) {
+	router := &customRouter{}
+	router.HandleFunc("/FOO/bar/%s", func(w http.ResponseWriter, r *http.Request) {})
+
+	req, err := http.NewRequest("GET", "/foo/bar/x", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+}
+
+func TestLowercaseStaticSegmentsParamClasses(t *testing.T) {
+	router := &customRouter{LowercaseStaticSegments: true}
+	router.HandleFunc("/x/{code:[A-Z]{3}}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "code %s\n", getParam(r, 1))
+	})
+	router.HandleFunc("/y/{fmt:pdf|csv}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "fmt %s\n", getParam(r, 1))
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "uppercase class",
+			path:           "/x/ABC",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "code ABC\n",
+		},
+		{
+			name:           "uppercase class with folded static segment",
+			path:           "/X/ABC",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "code ABC\n",
+		},
+		{
+			name:           "lowercase value rejected by uppercase class",
+			path:           "/x/abc",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+		{
+			name:           "enum value",
+			path:           "/Y/pdf",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "fmt pdf\n",
+		},
+		{
+			name:           "enum is case sensitive",
+			path:           "/y/PDF",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestLowercaseStaticSegmentsFoldsASCIIOnly(t *testing.T) {
+	router := &customRouter{LowercaseStaticSegments: true}
+	router.HandleFunc("/static/%s", func(w http.ResponseWriter, r *http.Request) {})
+	router.HandleFunc("/kit/%s", func(w http.ResponseWriter, r *http.Request) {})
+	router.HandleFunc(`/files/[a-c]+\.json/%s`, func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+	}{
+		{path: "/STATIC/x", expectedStatus: http.StatusOK},
+		{path: "/KiT/x", expectedStatus: http.StatusOK},
+		{path: "/FILES/AbC.JSON/x", expectedStatus: http.StatusOK},
+		// Unicode simple folding would map these to 's' and 'k'
+		{path: "/\u017ftatic/x", expectedStatus: http.StatusNotFound},
+		{path: "/\u212ait/x", expectedStatus: http.StatusNotFound},
+		{path: "/files/abd.json/x", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.URL.Path = tt.path
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != tt.expectedStatus {
+			t.Errorf("handler returned wrong status code for %q: got %v want %v", tt.path, status, tt.expectedStatus)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefineFragment registers a reusable template fragment that later templates reference as "{@name}",
+// i.e DefineFragment("base", "/api/v3") then "{@base}/users/%s". Fragments are expanded before the
+// template is compiled; a fragment's value may itself reference previously defined fragments.
+func (r *customRouter) DefineFragment(name, value string) error {
+	if !paramNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid fragment name '%s'", name)
+	}
+
+	expanded, err := r.expandFragments(value)
+	if err != nil {
+		return err
+	}
+
+	if r.fragments == nil {
+		r.fragments = make(map[string]string)
+	}
+	r.fragments[name] = expanded
+	return nil
+}
+
+// replaces every "{@name}" fragment reference in the template with its value,
+// erroring on references to fragments that haven't been defined
+func (r *customRouter) expandFragments(template string) (string, error) {
+	if !strings.Contains(template, "{@") {
+		return template, nil
+	}
+
+	var sb strings.Builder
+	rest := template
+	for {
+		start := strings.Index(rest, "{@")
+		if start == -1 {
+			sb.WriteString(rest)
+			return sb.String(), nil
+		}
+
+		end := strings.IndexByte(rest[start:], '}')
+		if end == -1 {
+			return "", fmt.Errorf("unterminated fragment reference in template '%s'", template)
+		}
+
+		name := rest[start+len("{@") : start+end]
+		value, ok := r.fragments[name]
+		if !ok {
+			return "", fmt.Errorf("undefined fragment '%s' in template '%s'", name, template)
+		}
+
+		sb.WriteString(rest[:start])
+		sb.WriteString(value)
+		rest = rest[start+end+1:]
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDefineFragment(t *testing.T) {
+	router := &customRouter{}
+	if err := router.DefineFragment("base", "/api/v3"); err != nil {
+		t.Fatal(err)
+	}
+	if err := router.DefineFragment("users", "{@base}/users/%s"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{template: "{@base}/users/%s", expected: "/api/v3/users/%s"},
+		{template: "{@users}/posts/{postID}", expected: "/api/v3/users/%s/posts/{postID}"},
+		{template: "/static/path", expected: "/static/path"},
+	}
+
+	for _, tt := range tests {
+		result, err := router.expandFragments(tt.template)
+		if err != nil {
+			t.Errorf("unexpected error expanding %q: %v", tt.template, err)
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("expandFragments(%q) = %q; want %q", tt.template, result, tt.expected)
+		}
+	}
+
+	router.HandleFunc("{@users}/profile", newDynamicPathHandler("/api/v3/users/%s/profile"))
+
+	req, err := http.NewRequest("GET", "/api/v3/users/u1/profile", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+}
+
+func TestUndefinedFragment(t *testing.T) {
+	router := &customRouter{}
+
+	_, err := router.expandFragments("{@base}/users/%s")
+	if err == nil || !strings.Contains(err.Error(), "undefined fragment 'base'") {
+		t.Errorf("expected an undefined fragment error, got %v", err)
+	}
+
+	if err := router.DefineFragment("users", "{@base}/users"); err == nil {
+		t.Error("expected an error defining a fragment that references an undefined fragment")
+	}
+
+	defer func() {
+		if recovered := recover(); recovered == nil {
+			t.Error("expected registering a template with an undefined fragment to panic")
+		}
+	}()
+	router.HandleFunc("{@base}/users/%s", func(w http.ResponseWriter, r *http.Request) {})
+}
+
+func TestFragmentTemplateLookup(t *testing.T) {
+	router := &customRouter{}
+	if err := router.DefineFragment("users", "/api/v3/users/{userID}"); err != nil {
+		t.Fatal(err)
+	}
+	router.HandleFunc("{@users}/profile", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(getParamByName(r, "uid") + " " + getQueryParam(r, "fields")))
+	})
+
+	// routes are looked up by the template as registered, fragment references and all
+	if err := router.BindQuery("{@users}/profile", []string{"fields"}); err != nil {
+		t.Fatalf("unexpected error binding query by fragment template: %v", err)
+	}
+	if err := router.AliasParam("{@users}/profile", "uid", "userID"); err != nil {
+		t.Errorf("unexpected error aliasing a param by fragment template: %v", err)
+	}
+	if err := router.BindQuery("{@missing}/profile", []string{"fields"}); err == nil {
+		t.Error("expected an error binding query to a template with an undefined fragment")
+	}
+
+	req, err := http.NewRequest("GET", "/api/v3/users/u1/profile?fields=name", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK || rr.Body.String() != "u1 name" {
+		t.Errorf("unexpected response: got %d %q", rr.Code, rr.Body.String())
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func FuzzMakeRegexPatternStr(f *testing.F) {
+	seeds := []string{
+		"/foo/bar/%s/baz/%s/qux",
+		"/api/v3/%s/%s/version",
+		"/geo/{coords:2}",
+		"/users/{userID}/posts/%s",
+		"/static/path",
+		"/a.b/(c)/[d]/*+?",
+		"/{unclosed",
+		"/{x:99999}",
+		"/{a}/{a}",
+		"$",
+		"%",
+		"",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, template string) {
+		// templates that can't be compiled (i.e invalid UTF-8) must be reported as an error, not a panic
+		pattern, err := compileTemplate(template)
+		if err != nil {
+			return
+		}
+
+		// static text is passed through as regex, so only a template without placeholders or regex
+		// syntax is sure to match itself
+		if !strings.ContainsAny(template, "%{") && regexp.QuoteMeta(template) == template &&
+			!pattern.MatchString(template) {
+			t.Errorf("template %q without placeholders doesn't match itself using %q", template, pattern)
+		}
+	})
+}
+
+func FuzzExtractParams(f *testing.F) {
+	f.Add("/foo/bar/%s/baz/%s/qux", "/foo/bar/alpha123/baz/beta456/qux")
+	f.Add("/geo/{coords:2}", "/geo/45.0/-93.0")
+	f.Add("/users/{userID}", "/users/")
+	f.Add("/static/path", "/static/path")
+	f.Add("%s%s%s", "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa!")
+
+	f.Fuzz(func(t *testing.T, template, path string) {
+		params, ok := ExtractParams(template, path)
+		if !ok {
+			if params != nil {
+				t.Errorf("ExtractParams(%q, %q) returned params %q without matching", template, path, params)
+			}
+			return
+		}
+
+		for _, param := range params {
+			if !strings.Contains(path, param) {
+				t.Errorf("ExtractParams(%q, %q) captured %q which isn't part of the path", template, path, param)
+			}
+		}
+	})
+}
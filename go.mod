@@ -1,3 +1,5 @@
 module dynamic-path-handler
 
 go 1.23.6
+
+require golang.org/x/text v0.21.0
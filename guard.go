@@ -0,0 +1,27 @@
+package main
+
+import "net/http"
+
+// AddGuard adds a check run on every request before routing, i.e for global auth or a maintenance mode.
+// Unlike middleware it sees requests that match no route. When guard returns false the request is
+// answered with its status, 403 if that's 0, and no route runs. Guards run in the order added.
+func (r *customRouter) AddGuard(guard func(r *http.Request) (allow bool, status int)) {
+	r.guards = append(r.guards, guard)
+}
+
+// runs the guards, writing the rejection of the first to refuse the request; reports whether routing may continue
+func (r *customRouter) runGuards(w http.ResponseWriter, req *http.Request) bool {
+	for _, guard := range r.guards {
+		allow, status := guard(req)
+		if allow {
+			continue
+		}
+		if status == 0 {
+			status = http.StatusForbidden
+		}
+		r.logger().Infof("Guard rejected %s %s with %d", req.Method, req.URL.Path, status)
+		r.writeError(w, status, http.StatusText(status))
+		return false
+	}
+	return true
+}
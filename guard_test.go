@@ -0,0 +1,60 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAddGuard(t *testing.T) {
+	maintenance := false
+	guardCalls := 0
+
+	router := &customRouter{}
+	router.AddGuard(func(r *http.Request) (bool, int) {
+		guardCalls++
+		return !maintenance, http.StatusServiceUnavailable
+	})
+	router.AddGuard(func(r *http.Request) (bool, int) {
+		return r.Header.Get("Authorization") != "", 0
+	})
+	router.HandleFunc("/users/%s", func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		name           string
+		path           string
+		maintenance    bool
+		authorized     bool
+		expectedStatus int
+	}{
+		{name: "guards allow", path: "/users/u1", authorized: true, expectedStatus: http.StatusOK},
+		{name: "maintenance blocks with 503", path: "/users/u1", maintenance: true, authorized: true, expectedStatus: http.StatusServiceUnavailable},
+		{name: "second guard blocks with the default 403", path: "/users/u1", expectedStatus: http.StatusForbidden},
+		{name: "unmatched path still guarded", path: "/missing", maintenance: true, expectedStatus: http.StatusServiceUnavailable},
+		{name: "unmatched path past the guards", path: "/missing", authorized: true, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maintenance = tt.maintenance
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.authorized {
+				req.Header.Set("Authorization", "Bearer token")
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+		})
+	}
+
+	if guardCalls != len(tests) {
+		t.Errorf("expected the first guard run once per request, got %d calls for %d requests", guardCalls, len(tests))
+	}
+}
@@ -0,0 +1,17 @@
+package main
+
+import "net/http"
+
+// HandleFuncHeaders registers a route whose responses carry the given static headers,
+// i.e "Cache-Control: max-age=60". Headers are set before the handler runs so it can still override them.
+func (r *customRouter) HandleFuncHeaders(pattern string, handler http.HandlerFunc, headers map[string]string) {
+	if handler == nil {
+		panic(nilHandlerError(pattern))
+	}
+	r.handle(pattern, func(w http.ResponseWriter, req *http.Request) {
+		for key, value := range headers {
+			w.Header().Set(key, value)
+		}
+		handler(w, req)
+	})
+}
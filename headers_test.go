@@ -0,0 +1,64 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleFuncHeaders(t *testing.T) {
+	router := &customRouter{}
+	headers := map[string]string{
+		"Cache-Control": "max-age=60",
+		"X-Route":       "static",
+	}
+
+	router.HandleFuncHeaders("/cached/%s", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}, headers)
+	router.HandleFuncHeaders("/override/%s", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+	}, headers)
+
+	tests := []struct {
+		name                 string
+		path                 string
+		expectedCacheControl string
+	}{
+		{
+			name:                 "headers appear on the response",
+			path:                 "/cached/abc",
+			expectedCacheControl: "max-age=60",
+		},
+		{
+			name:                 "handler override wins",
+			path:                 "/override/abc",
+			expectedCacheControl: "no-store",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != http.StatusOK {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+			}
+
+			if got := rr.Header().Get("Cache-Control"); got != tt.expectedCacheControl {
+				t.Errorf("unexpected Cache-Control header: got %q want %q", got, tt.expectedCacheControl)
+			}
+
+			if got := rr.Header().Get("X-Route"); got != "static" {
+				t.Errorf("unexpected X-Route header: got %q want %q", got, "static")
+			}
+		})
+	}
+}
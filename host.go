@@ -0,0 +1,39 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// HandleHost registers a route that only matches requests for the given host template, where
+// '{name}' placeholders capture labels of the host, i.e "{tenant}.example.com" makes the subdomain
+// available via getParamByName(r, "tenant"). Host params follow the path params positionally.
+func (r *customRouter) HandleHost(hostTemplate, pattern string, handler http.HandlerFunc) {
+	r.handle(pattern, handler, func(rt *route) {
+		// the request host is lowercased before matching, so only the template's static labels are folded;
+		// lowercasing the whole template would also rename its '{name}' params
+		hostRegexStr := makeRegexPatternStrWithOptions(hostTemplate, templateOptions{delimiter: '.', foldStatic: true, quoteStatic: true})
+		rt.host = regexp.MustCompile(hostRegexStr)
+	})
+}
+
+// matches the request host (without any port) against the route's host pattern, returning the captured
+// params and their names; routes without a host pattern match any host
+func (rt *route) matchHost(hostport string) ([]string, []string, bool) {
+	if rt.host == nil {
+		return nil, nil, true
+	}
+
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+
+	matches := rt.host.FindStringSubmatch(strings.ToLower(host))
+	if matches == nil {
+		return nil, nil, false
+	}
+	return matches[1:], rt.host.SubexpNames()[1:], true
+}
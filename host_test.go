@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleHost(t *testing.T) {
+	router := &customRouter{}
+	router.HandleHost("{tenant}.example.com", "/dashboard/%s", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "tenant %s, page %s\n", getParamByName(r, "tenant"), getParam(r, 1))
+	})
+
+	tests := []struct {
+		name           string
+		host           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "subdomain captured",
+			host:           "acme.example.com",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "tenant acme, page home\n",
+		},
+		{
+			name:           "port is ignored",
+			host:           "acme.example.com:8080",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "tenant acme, page home\n",
+		},
+		{
+			name:           "host is case-insensitive",
+			host:           "Globex.Example.COM",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "tenant globex, page home\n",
+		},
+		{
+			name:           "different domain",
+			host:           "acme.example.org",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+		{
+			name:           "no subdomain",
+			host:           "example.com",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+		{
+			name:           "nested subdomain",
+			host:           "a.acme.example.com",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/dashboard/home", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Host = tt.host
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v",
+					status, tt.expectedStatus)
+			}
+
+			if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %q want %q",
+					rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestHandleHostMixedCaseParamName(t *testing.T) {
+	router := &customRouter{}
+	router.HandleHost("{tenantID}.Example.com", "/dashboard", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "tenant %s\n", getParamByName(r, "tenantID"))
+	})
+
+	req, err := http.NewRequest("GET", "/dashboard", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Host = "Acme.example.com"
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK || rr.Body.String() != "tenant acme\n" {
+		t.Errorf("unexpected response: got %d %q want %d %q", rr.Code, rr.Body.String(), http.StatusOK, "tenant acme\n")
+	}
+
+	// the template's dots are literal, not regex wildcards
+	req.Host = "acme.exampleXcom"
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected a lookalike host not to match, got %d", rr.Code)
+	}
+}
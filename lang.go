@@ -0,0 +1,57 @@
+package main
+
+import (
+	"net/http"
+
+	"golang.org/x/text/language"
+)
+
+// context key for the language of the route chosen by language negotiation
+type languageKey struct{}
+
+// HandleFuncLang registers a route serving content in the given language, i.e "fr" or "pt-BR". Several
+// routes can share a template in different languages; the one the 'Accept-Language' header prefers is
+// used, falling back to a registration of the same template without a language.
+func (r *customRouter) HandleFuncLang(pattern, lang string, handler http.HandlerFunc) {
+	r.handle(pattern, handler, func(rt *route) {
+		rt.lang = lang
+	})
+}
+
+// returns the language of the route chosen for the request, or "" if it didn't declare one
+func negotiatedLanguage(r *http.Request) string {
+	lang, _ := r.Context().Value(languageKey{}).(string)
+	return lang
+}
+
+// picks among variants, routes that only differ by language, the one the 'Accept-Language' header
+// prefers using golang.org/x/text/language's matcher, falling back to the first language-less variant,
+// or to the first variant if there's none. Ties go to the earlier registration.
+func languageVariant(variants []*route, acceptLanguage string) *route {
+	var fallback *route
+	var languages []*route
+	var tags []language.Tag
+	for _, rt := range variants {
+		if rt.lang == "" {
+			if fallback == nil {
+				fallback = rt
+			}
+			continue
+		}
+		languages = append(languages, rt)
+		tags = append(tags, language.Make(rt.lang))
+	}
+
+	// an unparsable header is treated like an absent one
+	desired, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err == nil && len(desired) > 0 && len(tags) > 0 {
+		if _, index, confidence := language.NewMatcher(tags).Match(desired...); confidence != language.No {
+			return languages[index]
+		}
+	}
+
+	if fallback != nil {
+		return fallback
+	}
+	return variants[0]
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleFuncLang(t *testing.T) {
+	router := &customRouter{}
+	greeting := func(text string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "%s %s (%q)\n", text, getParam(r, 1), negotiatedLanguage(r))
+		}
+	}
+	router.HandleFuncLang("/greet/%s", "en", greeting("hello"))
+	router.HandleFuncLang("/greet/%s", "fr", greeting("bonjour"))
+	router.HandleFunc("/greet/%s", greeting("hi"))
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		expectedBody   string
+	}{
+		{name: "french", acceptLanguage: "fr", expectedBody: "bonjour ann (\"fr\")\n"},
+		{name: "regional french", acceptLanguage: "fr-CA", expectedBody: "bonjour ann (\"fr\")\n"},
+		{name: "quality preference", acceptLanguage: "en;q=0.5, fr;q=0.8", expectedBody: "bonjour ann (\"fr\")\n"},
+		{name: "english", acceptLanguage: "en-GB, fr;q=0.1", expectedBody: "hello ann (\"en\")\n"},
+		{name: "no header falls back", acceptLanguage: "", expectedBody: "hi ann (\"\")\n"},
+		{name: "unsupported language falls back", acceptLanguage: "de", expectedBody: "hi ann (\"\")\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/greet/ann", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != http.StatusOK {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+			}
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// creates an http.HandlerFunc that sets Last-Modified from lastMod(params) and answers 304 Not Modified
+// when the request's If-Modified-Since is not older than it, otherwise it calls next.
+// A malformed If-Modified-Since is treated as if it were absent
+func newLastModifiedHandler(template string, next http.HandlerFunc, lastMod func(params []string) time.Time) http.HandlerFunc {
+	pathPattern := regexp.MustCompile(makeRegexPatternStr(template))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		matches := pathPattern.FindStringSubmatch(r.URL.Path)
+		if matches == nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		// HTTP dates only have second precision
+		modified := lastMod(matches[1:]).UTC().Truncate(time.Second)
+		if !modified.IsZero() {
+			w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+		}
+
+		if notModifiedSince(r, modified) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// reports whether the conditional GET/HEAD request already has the representation modified at modified
+func notModifiedSince(r *http.Request, modified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	if modified.IsZero() {
+		return false
+	}
+
+	header := r.Header.Get("If-Modified-Since")
+	if header == "" {
+		return false
+	}
+	since, err := http.ParseTime(header)
+	if err != nil {
+		return false
+	}
+	return !modified.After(since)
+}
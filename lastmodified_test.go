@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLastModifiedHandler(t *testing.T) {
+	modified := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	next := func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "fresh body")
+	}
+	handler := newLastModifiedHandler("/docs/%s", next, func(params []string) time.Time {
+		if params[0] != "readme" {
+			t.Errorf("unexpected params: %v", params)
+		}
+		return modified
+	})
+
+	tests := []struct {
+		name            string
+		ifModifiedSince string
+		expectedStatus  int
+		expectedBody    string
+	}{
+		{
+			name:           "no condition",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "fresh body\n",
+		},
+		{
+			name:            "not modified since same time",
+			ifModifiedSince: modified.Format(http.TimeFormat),
+			expectedStatus:  http.StatusNotModified,
+		},
+		{
+			name:            "not modified since later time",
+			ifModifiedSince: modified.Add(time.Hour).Format(http.TimeFormat),
+			expectedStatus:  http.StatusNotModified,
+		},
+		{
+			name:            "modified after condition",
+			ifModifiedSince: modified.Add(-time.Hour).Format(http.TimeFormat),
+			expectedStatus:  http.StatusOK,
+			expectedBody:    "fresh body\n",
+		},
+		{
+			name:            "malformed date ignored",
+			ifModifiedSince: "yesterday-ish",
+			expectedStatus:  http.StatusOK,
+			expectedBody:    "fresh body\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/docs/readme", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.ifModifiedSince != "" {
+				req.Header.Set("If-Modified-Since", tt.ifModifiedSince)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+			if got := rr.Header().Get("Last-Modified"); got != modified.Format(http.TimeFormat) {
+				t.Errorf("unexpected Last-Modified header: got %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package main
+
+import "net/http"
+
+// responds to a request rejected for its size, i.e 414 for a path over MaxPathLength, using
+// RequestTooLargeHandler when one is configured
+func (r *customRouter) requestTooLarge(w http.ResponseWriter, req *http.Request, status int) {
+	if r.RequestTooLargeHandler != nil {
+		r.RequestTooLargeHandler.ServeHTTP(w, req)
+		return
+	}
+	r.writeError(w, status, http.StatusText(status))
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMaxPathLength(t *testing.T) {
+	customHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusRequestURITooLong)
+		fmt.Fprintln(w, `{"error":"path too long"}`)
+	})
+
+	tests := []struct {
+		name           string
+		handler        http.Handler
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "path within the limit",
+			path:           "/items/abc",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "item abc\n",
+		},
+		{
+			name:           "default response over the limit",
+			path:           "/items/" + strings.Repeat("a", 32),
+			expectedStatus: http.StatusRequestURITooLong,
+			expectedBody:   "Request URI Too Long\n",
+		},
+		{
+			name:           "custom handler over the limit",
+			handler:        customHandler,
+			path:           "/items/" + strings.Repeat("a", 32),
+			expectedStatus: http.StatusRequestURITooLong,
+			expectedBody:   `{"error":"path too long"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := &customRouter{MaxPathLength: 32, RequestTooLargeHandler: tt.handler}
+			router.HandleFunc("/items/%s", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, "item %s\n", getParam(r, 1))
+			})
+
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import "net/http"
+
+// HandleFuncLocalOnly registers a route only serving clients on loopback or private addresses, i.e for
+// admin endpoints; everyone else gets a 403. The client IP honors TrustedProxies.
+func (r *customRouter) HandleFuncLocalOnly(pattern string, handler http.HandlerFunc) {
+	if handler == nil {
+		panic(nilHandlerError(pattern))
+	}
+	r.handle(pattern, func(w http.ResponseWriter, req *http.Request) {
+		ip, ok := r.clientIP(req)
+		if !ok || !(ip.IsLoopback() || ip.IsPrivate()) {
+			r.logger().Infof("Rejected %s %s from non-local client %s", req.Method, req.URL.Path, req.RemoteAddr)
+			r.writeError(w, http.StatusForbidden, "Forbidden")
+			return
+		}
+		handler(w, req)
+	})
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestHandleFuncLocalOnly(t *testing.T) {
+	router := &customRouter{TrustedProxies: []netip.Prefix{netip.MustParsePrefix("203.0.113.0/24")}}
+	router.HandleFuncLocalOnly("/admin/%s", func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		name           string
+		remoteAddr     string
+		forwardedFor   string
+		expectedStatus int
+	}{
+		{name: "loopback", remoteAddr: "127.0.0.1:5555", expectedStatus: http.StatusOK},
+		{name: "ipv6 loopback", remoteAddr: "[::1]:5555", expectedStatus: http.StatusOK},
+		{name: "private range", remoteAddr: "192.168.1.20:5555", expectedStatus: http.StatusOK},
+		{name: "public", remoteAddr: "198.51.100.7:5555", expectedStatus: http.StatusForbidden},
+		{
+			name:           "forwarded header from an untrusted client ignored",
+			remoteAddr:     "198.51.100.7:5555",
+			forwardedFor:   "127.0.0.1",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "private client behind a trusted proxy",
+			remoteAddr:     "203.0.113.10:5555",
+			forwardedFor:   "10.1.2.3",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "public client behind a trusted proxy",
+			remoteAddr:     "203.0.113.10:5555",
+			forwardedFor:   "10.1.2.3, 198.51.100.7",
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/admin/stats", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+// behind a proxy on a private range, a chain without a valid client must not leave the proxy's own
+// address to pass as a local client
+func TestHandleFuncLocalOnlyMalformedForwardedFor(t *testing.T) {
+	router := &customRouter{TrustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}}
+	router.HandleFuncLocalOnly("/admin/%s", func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		name           string
+		forwardedFor   string
+		expectedStatus int
+	}{
+		{name: "ip:port hop", forwardedFor: "203.0.113.9:4444", expectedStatus: http.StatusForbidden},
+		{name: "unknown hop", forwardedFor: "unknown", expectedStatus: http.StatusForbidden},
+		{name: "malformed hop behind a valid one", forwardedFor: "192.168.1.5, unknown", expectedStatus: http.StatusForbidden},
+		{name: "only trusted hops", forwardedFor: "10.1.1.1, 10.2.2.2", expectedStatus: http.StatusForbidden},
+		{name: "no header", expectedStatus: http.StatusForbidden},
+		{name: "public client", forwardedFor: "203.0.113.9", expectedStatus: http.StatusForbidden},
+		{name: "private client", forwardedFor: "192.168.1.5", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/admin/stats", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.RemoteAddr = "10.0.0.2:5555"
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+		})
+	}
+}
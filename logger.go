@@ -0,0 +1,92 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+)
+
+// Logger is a minimal leveled logger so router output can be routed into a structured logging setup
+type Logger interface {
+	Debugf(format string, args ...any)
+	Infof(format string, args ...any)
+	Errorf(format string, args ...any)
+}
+
+// default Logger writing to the stdlib log package with the level as a prefix
+type stdLogger struct{}
+
+func (stdLogger) Debugf(format string, args ...any) { log.Printf("DEBUG "+format, args...) }
+func (stdLogger) Infof(format string, args ...any)  { log.Printf("INFO "+format, args...) }
+func (stdLogger) Errorf(format string, args ...any) { log.Printf("ERROR "+format, args...) }
+
+// returns the configured Logger, falling back to the stdlib log package, with LogPrefix applied
+func (r *customRouter) logger() Logger {
+	var logger Logger = stdLogger{}
+	if r.Logger != nil {
+		logger = r.Logger
+	}
+	if r.LogPrefix != "" {
+		logger = prefixLogger{prefix: r.LogPrefix, next: logger}
+	}
+	return logger
+}
+
+// Logger prepending a fixed prefix to every message before passing it on
+type prefixLogger struct {
+	prefix string
+	next   Logger
+}
+
+// the prefix is passed as an argument so any '%' in it isn't treated as a verb
+func (l prefixLogger) Debugf(format string, args ...any) {
+	l.next.Debugf("%s"+format, append([]any{l.prefix}, args...)...)
+}
+
+func (l prefixLogger) Infof(format string, args ...any) {
+	l.next.Infof("%s"+format, append([]any{l.prefix}, args...)...)
+}
+
+func (l prefixLogger) Errorf(format string, args ...any) {
+	l.next.Errorf("%s"+format, append([]any{l.prefix}, args...)...)
+}
+
+// context key for the function building the logger tagged with the matched route, see requestLogger
+type requestLoggerKey struct{}
+
+// returns a Logger whose lines are tagged with the matched template, the method and the request ID,
+// i.e "template=/users/%s method=GET request_id=abc123 ...", so handler logs can be correlated.
+// Falls back to the stdlib log package outside of a router.
+func requestLogger(r *http.Request) Logger {
+	if logger, ok := r.Context().Value(requestLoggerKey{}).(func() Logger); ok {
+		return logger()
+	}
+	return stdLogger{}
+}
+
+// returns a function building the logger for requestLogger from the router's logger on its first call,
+// so requests whose handler never logs don't pay for formatting it or generating a request ID
+func (r *customRouter) routeLogger(rt *route, req *http.Request) func() Logger {
+	return sync.OnceValue(func() Logger {
+		fields := fmt.Sprintf("template=%s method=%s request_id=%s ", rt.template, req.Method, requestID(req))
+		return prefixLogger{prefix: fields, next: r.logger()}
+	})
+}
+
+// returns the ID already assigned to the request, its 'X-Request-ID', or a random ID if it has neither
+func requestID(req *http.Request) string {
+	if id, ok := req.Context().Value(requestIDKey{}).(string); ok {
+		return id
+	}
+	if id := req.Header.Get("X-Request-ID"); id != "" {
+		return id
+	}
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
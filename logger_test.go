@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Logger recording every message along with its level, for asserting which level an outcome used
+type capturingLogger struct {
+	levels   []string
+	messages []string
+}
+
+func (l *capturingLogger) Debugf(format string, args ...any) { l.record("debug", format, args...) }
+func (l *capturingLogger) Infof(format string, args ...any)  { l.record("info", format, args...) }
+func (l *capturingLogger) Errorf(format string, args ...any) { l.record("error", format, args...) }
+
+func (l *capturingLogger) record(level, format string, args ...any) {
+	l.levels = append(l.levels, level)
+	l.messages = append(l.messages, fmt.Sprintf(format, args...))
+}
+
+func TestRouterLogLevels(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedLevels []string
+	}{
+		{
+			name:           "match logged at debug",
+			path:           "/ok/abc",
+			expectedStatus: http.StatusOK,
+			expectedLevels: []string{"debug"},
+		},
+		{
+			name:           "miss logged at info",
+			path:           "/missing",
+			expectedStatus: http.StatusNotFound,
+			expectedLevels: []string{"info"},
+		},
+		{
+			name:           "500 logged at error",
+			path:           "/fail/abc",
+			expectedStatus: http.StatusInternalServerError,
+			expectedLevels: []string{"debug", "error"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &capturingLogger{}
+			router := &customRouter{Logger: logger}
+			router.HandleFunc("/ok/%s", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintln(w, "ok")
+			})
+			router.HandleFunc("/fail/%s", func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "downstream unavailable", http.StatusInternalServerError)
+			})
+			// only the request's outcome is of interest, not the registrations
+			*logger = capturingLogger{}
+
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			if !slices.Equal(logger.levels, tt.expectedLevels) {
+				t.Errorf("unexpected log levels: got %v want %v (messages: %q)", logger.levels, tt.expectedLevels, logger.messages)
+			}
+		})
+	}
+}
+
+func TestRouterLogPrefix(t *testing.T) {
+	logger := &capturingLogger{}
+	router := &customRouter{Logger: logger, LogPrefix: "[admin 100%] "}
+	router.HandleFunc("/ok/%s", func(w http.ResponseWriter, r *http.Request) {})
+
+	for _, path := range []string{"/ok/abc", "/missing"} {
+		req, err := http.NewRequest("GET", path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	expected := []string{
+		"[admin 100%] Registering route '/ok/%s' as ^/ok/([a-zA-Z0-9]+)$",
+		"[admin 100%] Matched route '/ok/%s' for GET /ok/abc",
+		"[admin 100%] No route matched GET /missing (NoMatch)",
+	}
+	if !slices.Equal(logger.messages, expected) {
+		t.Errorf("unexpected log messages: got %q want %q", logger.messages, expected)
+	}
+}
+
+func TestRequestLogger(t *testing.T) {
+	logger := &capturingLogger{}
+	router := &customRouter{Logger: logger, LogPrefix: "[api] "}
+	router.HandleFunc("/users/%s", func(w http.ResponseWriter, r *http.Request) {
+		requestLogger(r).Infof("loading user %s", getParam(r, 1))
+	})
+
+	req, err := http.NewRequest("GET", "/users/u1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Request-ID", "req-42")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	expected := "[api] template=/users/%s method=GET request_id=req-42 loading user u1"
+	if !slices.Contains(logger.messages, expected) {
+		t.Errorf("expected the handler's line %q, got %q", expected, logger.messages)
+	}
+}
+
+func TestRequestLoggerGeneratesRequestID(t *testing.T) {
+	req, err := http.NewRequest("GET", "/users/u1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, second := requestID(req), requestID(req)
+	if first == "" || first == second {
+		t.Errorf("expected distinct generated IDs, got %q and %q", first, second)
+	}
+}
+
+func TestRequestLoggerBuiltOnce(t *testing.T) {
+	logger := &capturingLogger{}
+	router := &customRouter{Logger: logger}
+	router.HandleFunc("/users/%s", func(w http.ResponseWriter, r *http.Request) {
+		requestLogger(r).Infof("first")
+		requestLogger(r).Infof("second")
+	})
+
+	// without an 'X-Request-ID' the generated ID must be the same for every line of the request
+	req, err := http.NewRequest("GET", "/users/u1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	var lines []string
+	for _, message := range logger.messages {
+		if strings.HasSuffix(message, "first") || strings.HasSuffix(message, "second") {
+			lines = append(lines, message)
+		}
+	}
+	if len(lines) != 2 || strings.TrimSuffix(lines[0], "first") != strings.TrimSuffix(lines[1], "second") {
+		t.Errorf("expected both lines tagged alike, got %q", lines)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import "slices"
+
+// registers rt, evicting the least recently used routes if that takes the router over MaxRoutes
+func (r *customRouter) addRoute(rt *route) {
+	r.logger().Debugf("Registering route '%s' as %s", rt.template, rt.pattern)
+	r.routesMu.Lock()
+	defer r.routesMu.Unlock()
+	rt.touch(r)
+	markVariants(rt, r.routes)
+	r.routes = append(r.routes, rt)
+	r.evictRoutes()
+}
+
+// returns the registered routes for iterating without holding routesMu. Routes are only ever appended
+// past the end of the slice or removed by copying it, so a snapshot is never modified underneath a reader.
+func (r *customRouter) routeSnapshot() []*route {
+	r.routesMu.RLock()
+	defer r.routesMu.RUnlock()
+	return r.routes
+}
+
+// notes rt as just used, keeping it from eviction longest
+func (rt *route) touch(r *customRouter) {
+	rt.lastUsed.Store(r.useClock.Add(1))
+}
+
+// removes the least recently matched (or registered) routes until at most MaxRoutes remain; routesMu
+// must be held for writing
+func (r *customRouter) evictRoutes() {
+	if r.MaxRoutes <= 0 {
+		return
+	}
+	for len(r.routes) > r.MaxRoutes {
+		// on a tie the earlier registered route goes first
+		oldest := 0
+		for i, rt := range r.routes {
+			if rt.lastUsed.Load() < r.routes[oldest].lastUsed.Load() {
+				oldest = i
+			}
+		}
+		r.logger().Infof("Evicting least recently used route '%s' to stay within %d routes",
+			r.routes[oldest].template, r.MaxRoutes)
+		// copied rather than deleted in place, which would shift routes under snapshots being served
+		r.routes = slices.Concat(r.routes[:oldest], r.routes[oldest+1:])
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestMaxRoutesEvictsLeastRecentlyUsed(t *testing.T) {
+	router := &customRouter{MaxRoutes: 3}
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	router.HandleFunc("/a/%s", handler)
+	router.HandleFunc("/b/%s", handler)
+	router.HandleFunc("/c/%s", handler)
+
+	serve := func(path string) int {
+		req, err := http.NewRequest("GET", path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	// "/b/%s" becomes the least recently used after "/a/%s" and "/c/%s" are matched
+	serve("/a/x")
+	serve("/c/x")
+	router.HandleFunc("/d/%s", handler)
+
+	if len(router.routes) != 3 {
+		t.Fatalf("expected the router capped at 3 routes, got %d", len(router.routes))
+	}
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+	}{
+		{path: "/a/x", expectedStatus: http.StatusOK},
+		{path: "/b/x", expectedStatus: http.StatusNotFound},
+		{path: "/c/x", expectedStatus: http.StatusOK},
+		{path: "/d/x", expectedStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		if status := serve(tt.path); status != tt.expectedStatus {
+			t.Errorf("%s: got status %d want %d", tt.path, status, tt.expectedStatus)
+		}
+	}
+
+	// of the routes left "/a/%s" was matched longest ago by the checks above
+	router.HandleFunc("/e/%s", handler)
+	if status := serve("/a/x"); status != http.StatusNotFound {
+		t.Errorf("expected the least recently used route evicted, got status %d", status)
+	}
+	if status := serve("/e/x"); status != http.StatusOK {
+		t.Errorf("expected the new route registered, got status %d", status)
+	}
+}
+
+func TestMaxRoutesUnlimited(t *testing.T) {
+	router := &customRouter{}
+	for _, template := range []string{"/a", "/b", "/c", "/d"} {
+		router.HandleFunc(template, func(w http.ResponseWriter, r *http.Request) {})
+	}
+	if len(router.routes) != 4 {
+		t.Errorf("expected no eviction without MaxRoutes, got %d routes", len(router.routes))
+	}
+}
+
+// registers routes, with per-route settings and evictions, while requests are served; run with -race
+func TestRegisterWhileServing(t *testing.T) {
+	router := &customRouter{MaxRoutes: 20}
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	router.HandleFunc("/stable/%s", handler)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range 100 {
+			router.HandleMethodFunc(http.MethodPost, fmt.Sprintf("/dynamic%d/%%s", i), handler)
+			router.HandleHost("api.example.com", fmt.Sprintf("/hosted%d/%%s", i), handler)
+		}
+	}()
+
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 200 {
+				req, err := http.NewRequest("GET", fmt.Sprintf("/dynamic%d/x", i%100), nil)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				router.ServeHTTP(httptest.NewRecorder(), req)
+				router.Match(http.MethodPost, "/dynamic1/x")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := len(router.Routes()); n != 20 {
+		t.Errorf("expected MaxRoutes to cap the routes at 20, got %d", n)
+	}
+}
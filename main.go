@@ -4,17 +4,24 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"math/rand/v2"
 	"net/http"
+	"net/netip"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // creates a new handler function for the provided path pattern
 func newDynamicPathHandler(pathPattern string) http.HandlerFunc {
-	replacedRoute := strings.Replace(pathPattern, "%s", "([a-zA-Z0-9]+)", -1)
+	replacedRoute := makeRegexPatternStr(pathPattern)
 	log.Printf("Adding handler: %s\n", replacedRoute)
-	fullPattern := regexp.MustCompile("^" + replacedRoute + "$")
+	fullPattern := regexp.MustCompile(replacedRoute)
 
 	// determine the number of path parameters
 	numGroups := fullPattern.NumSubexp()
@@ -44,31 +51,57 @@ func newDynamicPathHandler(pathPattern string) http.HandlerFunc {
 
 // Convert a provided pattern path pattern from i.e "/foo/bar/%s/baz/%s/qux" to a proper alphanumeric regex
 func makeRegexPatternStr(pattern string) string {
-	return "^" + strings.Replace(pattern, "%s", "([a-zA-Z0-9]+)", -1) + "$"
+	return makeRegexPatternStrWithOptions(pattern, templateOptions{})
+}
+
+// same as makeRegexPatternStr but compiled with the given options, i.e a custom segment delimiter
+func makeRegexPatternStrWithOptions(pattern string, opts templateOptions) string {
+	return "^" + templateToRegex(pattern, opts) + "$"
 }
 
 // creates an http.HandlerFunc that matches the request path against the provided templated path and extracts parameters
 // i.e provide "/foo/bar/%s/baz/%s/qux" and it will match paths like "/foo/bar/123/baz/456/qux"
 func newPathRegexHandler(routeTemplateStr string) http.HandlerFunc {
+	return newPathRegexHandlerForMethod(routeTemplateStr, http.MethodGet)
+}
+
+// like newPathRegexHandler but serving the given methods instead of only GET, i.e "POST" and "PUT" for a
+// write API. A path that doesn't match is a 404 whatever the method; a matching path requested with
+// another method is a 405 with an 'Allow' header listing the methods. No methods means GET.
+func newPathRegexHandlerForMethod(routeTemplateStr string, methods ...string) http.HandlerFunc {
 	// internally generate the regex pattern from the template
 	regexPatternStr := makeRegexPatternStr(routeTemplateStr)
 	pathPattern := regexp.MustCompile(regexPatternStr)
 	numGroups := pathPattern.NumSubexp()
 
-	return func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
+	allowed := []string{http.MethodGet}
+	if len(methods) > 0 {
+		allowed = make([]string, len(methods))
+		for i, method := range methods {
+			allowed[i] = strings.ToUpper(method)
 		}
+	}
 
-		matches := pathPattern.FindStringSubmatch(r.URL.Path)
+	return func(w http.ResponseWriter, r *http.Request) {
+		indices := pathPattern.FindStringSubmatchIndex(r.URL.Path)
 		// The handler must ensure the *full* path matches the specific regex.
-		if matches == nil {
+		if indices == nil {
 			log.Printf("No matches for pattern '%s' in path '%s'", regexPatternStr, r.URL.Path)
 			http.NotFound(w, r)
 			return
 		}
 
+		if !slices.Contains(allowed, r.Method) {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		buf := acquireSubmatches()
+		defer buf.release()
+		buf.fill(r.URL.Path, indices)
+		matches := buf.values
+
 		// matches[0] is the full string, we're only interested in the capturing groups
 		if len(matches)-1 != numGroups {
 			log.Printf("Error: Expected %d capturing groups, got %d from path '%s' with pattern '%s'",
@@ -94,59 +127,436 @@ func newPathRegexHandler(routeTemplateStr string) http.HandlerFunc {
 
 // associates a pattern with a handler
 type route struct {
-	pattern *regexp.Regexp   // compiled regex pattern matching a path, i.e "/foo/bar/%s/baz/%s/qux"
-	handler http.HandlerFunc // handler function to call when the pattern matches
+	template   string            // template the route was registered with, i.e "/foo/bar/%s/baz/%s/qux"
+	pattern    *regexp.Regexp    // compiled regex pattern matching a path, i.e "/foo/bar/%s/baz/%s/qux"
+	structural *regexp.Regexp    // same shape as pattern but with any segment accepted for each param
+	handler    http.HandlerFunc  // handler function to call when the pattern matches
+	method     string            // HTTP method the route serves, GET when empty
+	host       *regexp.Regexp    // optional pattern the request host must match, i.e "{tenant}.example.com"
+	queryKeys  []string          // query params parsed once and exposed via getQueryParam
+	aliases    map[string]string // old param names resolved to their current name by getParamByName
+	query      []queryMatcher    // query params the request must carry, from the part of the template after '?'
+	produces   []string          // media types the handler can respond with, negotiated against 'Accept'
+	lang       string            // language tag the route serves, negotiated against 'Accept-Language'
+	budget     time.Duration     // processing budget set as the request context's deadline; 0 means none
+	cookie     *http.Cookie      // cookie name and value the request must carry, preferred over cookie-less variants
+	params     []ParamInfo       // path params declared by the template, see RouteParamInfo
+	remainder  bool              // the last path group captures the rest of the path, see HandlePrefix
+	enabled    func() bool       // evaluated per request, the route is skipped while it reports false
+	allowSets  map[int]*sync.Map // runtime-updatable sets of values allowed per 1-indexed param
+	paramCount int               // for regex routes, how many groups must participate in a match; 0 skips the check
+
+	validators map[int]func(string) error // optional validators keyed by 1-indexed param position
+	accepts    func(*http.Request) bool   // further requirement on the request, i.e a minimum API version header
+
+	lastUsed    atomic.Uint64 // tick of the router's useClock when last matched or registered, see MaxRoutes
+	hasVariants atomic.Bool   // another route only differs by cookie or language, see selectVariant
 }
 
 type customRouter struct {
-	routes []*route
+	// registered routes; routes may be added while serving so they're read through routeSnapshot
+	routes   []*route
+	routesMu sync.RWMutex
+
+	// Delimiter separates path segments when matching, i.e '.' so "a.%s.c" matches "a.x.c".
+	// Defaults to '/'; it applies to routes registered after it is set. With a custom delimiter the
+	// static text of templates is matched literally rather than as regex.
+	Delimiter byte
+
+	// AllowMethodOverride lets POST requests choose the method used for routing via the
+	// 'X-HTTP-Method-Override' header or the '_method' query param, for clients like HTML forms
+	AllowMethodOverride bool
+
+	// IgnoreTrailingSlash makes "/foo/bar/" and "/foo/bar" match the same route, without a redirect.
+	// A single trailing slash is trimmed from templates registered after it is set and from every request path.
+	// It's the alternative to redirecting to the canonical path, RedirectTrailingSlash in routers like
+	// httprouter, and the two are mutually exclusive: with the slash trimmed before matching both forms are
+	// the same request, so Redirect("/foo/bar/", "/foo/bar", ...) would redirect "/foo/bar" to itself.
+	IgnoreTrailingSlash bool
+
+	// OnMatch is called with Matched when a route is selected to serve the request
+	OnMatch func(req *http.Request, outcome MatchOutcome)
+	// OnMiss is called when no route matches the path, with StructuralOnly if the path has the shape of
+	// a registered route but a param failed its class (a malformed request to a known endpoint) or NoMatch otherwise
+	OnMiss func(req *http.Request, outcome MatchOutcome)
+
+	// Logger receives request outcomes: matches at debug, misses at info and 5xx responses at error.
+	// Defaults to the stdlib log package.
+	Logger Logger
+
+	// LogPrefix is prepended to every log line so multiple routers in one process can be told apart,
+	// i.e "[admin] "
+	LogPrefix string
+
+	// MaxPathLength rejects request paths longer than this many bytes with a 414; 0 means no limit
+	MaxPathLength int
+	// RequestTooLargeHandler replaces the default response when a request is rejected for its size
+	RequestTooLargeHandler http.Handler
+
+	// JSONErrors makes every error response the router writes itself (404, 405, 422, 500 and so on) a JSON
+	// envelope like {"error":{"code":404,"message":"..."}} instead of plain text
+	JSONErrors bool
+
+	// LowercaseStaticSegments matches the static segments of templates registered after it is set ignoring
+	// ASCII case, so "/FOO/bar/%s" matches "/foo/BAR/x"; other characters must match exactly. Params are
+	// matched against and captured from the path exactly as sent, so "/x/{code:[A-Z]{3}}" still requires
+	// uppercase letters.
+	LowercaseStaticSegments bool
+
+	// RejectEarlyData answers state-changing requests sent as TLS 1.3 early data (marked 'Early-Data: 1'
+	// by the terminating proxy) with a 425 Too Early so they can't be replayed; safe methods are unaffected
+	RejectEarlyData bool
+
+	// RejectDuplicateQueryKeys answers requests repeating a query param, i.e "?id=1&id=2", with a 400
+	// to guard against parameter pollution
+	RejectDuplicateQueryKeys bool
+
+	// RedactQueryKeys are query params whose values, i.e secrets like "token", are logged as REDACTED.
+	// The router's log lines for requests include their query, with these redacted.
+	RedactQueryKeys []string
+
+	// AccessLogJSON writes one JSON object per request to AccessLogOutput with its method, path, matched
+	// template, status, response bytes, duration in milliseconds and request ID
+	AccessLogJSON bool
+	// AccessLogOutput receives the JSON access log, defaults to stderr
+	AccessLogOutput io.Writer
+
+	// Rand picks the variant of HandleFuncWeighted routes, i.e rand.New(rand.NewPCG(1, 2)) for
+	// reproducible picks in tests; defaults to a randomly seeded source
+	Rand   *rand.Rand
+	randMu sync.Mutex
+
+	// MaxRoutes caps how many routes may be registered, i.e when routes are added at runtime; registering
+	// beyond it evicts the least recently matched route. 0 means no limit.
+	// Registering routes is safe while serving; changing settings of registered routes, i.e AliasParam, isn't.
+	MaxRoutes int
+	// ticks ordering route use for MaxRoutes eviction
+	useClock atomic.Uint64
+
+	// PathRewrite maps the request path before matching, i.e "/v1/..." to "/v3/..." for legacy URLs.
+	// It's applied once per request and handlers see the rewritten path.
+	PathRewrite func(path string) string
+
+	// Now is the time source for time based routes like HandleFuncScheduled, defaults to time.Now
+	Now func() time.Time
+
+	// RetryAfter is the 'Retry-After' delay sent with 503s whose wait isn't known, i.e while a circuit
+	// breaker's trial request is in flight or after a scheduled route's window closed; defaults to 30s
+	RetryAfter time.Duration
+
+	// TrustedProxies are the proxies whose 'X-Forwarded-For' header is believed when determining the
+	// client IP, i.e netip.MustParsePrefix("10.0.0.0/8"); the connection's address is used otherwise
+	TrustedProxies []netip.Prefix
+
+	// CSRFTokenStore issues and checks the tokens used by CSRFProtect, defaults to an in-memory store
+	CSRFTokenStore CSRFTokenStore
+
+	// reusable template fragments referenced as "{@name}", see DefineFragment
+	fragments map[string]string
+
+	// tried in order when no route matches, see AddNotFoundFallback
+	notFoundFallbacks []http.Handler
+
+	// wraps the handler of every matched route, the first added being outermost, see use
+	middleware []func(http.HandlerFunc) http.HandlerFunc
+
+	// checked in order before any route is matched, see AddGuard
+	guards []func(*http.Request) (bool, int)
+
+	// serves requests no route matches, see HandleFuncCatchAll
+	catchAll http.HandlerFunc
+
+	// jobs started by the router's async handlers, see newAsyncHandler
+	asyncJobs *asyncJobStore
 }
 
 // adds a list of a template routes to customRouter
 func (r *customRouter) addTemplateRoutes(routeTemplates []string) {
-	for _, routeTemplate := range routeTemplates {
-		r.HandleFunc(routeTemplate, newDynamicPathHandler(routeTemplate))
+	r.HandleTemplates(routeTemplates, newDynamicPathHandler)
+}
+
+// registers each template with the handler the factory builds for it, i.e so the handler can close over its template
+func (r *customRouter) HandleTemplates(templates []string, factory func(template string) http.HandlerFunc) {
+	for _, template := range templates {
+		r.handle(template, factory(template))
 	}
 }
 
-// register a new route with a template pattern and handler
+// registers a new GET route on the template pattern, i.e "/foo/bar/%s/baz/%s/qux"; see HandleMethodFunc
+// for serving other methods
 func (r *customRouter) HandleFunc(pattern string, handler http.HandlerFunc) {
-	// Convert the pattern from "/foo/bar/%s/baz/%s/qux" to a proper alphanumeric regex
-	replacedRoute := strings.Replace(pattern, "%s", "([a-zA-Z0-9]+)", -1)
-	log.Printf("Registering route: %s\n", replacedRoute)
-	fullPattern := regexp.MustCompile("^" + replacedRoute + "$")
-	r.routes = append(r.routes, &route{
-		pattern: fullPattern,
-		handler: handler,
+	r.handle(pattern, handler)
+}
+
+// registers a new route serving method on the template pattern, i.e "POST" and "/api/v3/%s/%s"; an empty
+// method means GET. Requests for a path some route matches with a method none serves get a 405 whose
+// 'Allow' header lists the methods registered for the path.
+func (r *customRouter) HandleMethodFunc(method, pattern string, handler http.HandlerFunc) {
+	r.handle(pattern, handler, func(rt *route) {
+		rt.method = strings.ToUpper(method)
 	})
 }
 
+// registers the route after applying configure, so callers can attach per-route settings before it can
+// serve requests; panics if the template can't be compiled, see HandleFuncAll for collecting errors instead
+func (r *customRouter) handle(pattern string, handler http.HandlerFunc, configure ...func(*route)) {
+	rt, err := r.compileRoute(pattern, handler)
+	if err != nil {
+		panic(err)
+	}
+	for _, fn := range configure {
+		fn(rt)
+	}
+	r.addRoute(rt)
+}
+
+// the error registering a nil handler panics with; helpers wrapping handler in a closure check it
+// themselves before wrapping, since the closure is never nil
+func nilHandlerError(pattern string) error {
+	return fmt.Errorf("nil handler for template '%s'", pattern)
+}
+
+// builds a route for the template without registering it
+func (r *customRouter) compileRoute(pattern string, handler http.HandlerFunc) (*route, error) {
+	return r.compileRouteClasses(pattern, handler, nil)
+}
+
+// like compileRoute with classes overriding the class of the template's '%s' params, see HandleFuncClasses
+func (r *customRouter) compileRouteClasses(pattern string, handler http.HandlerFunc, classes []string) (*route, error) {
+	if handler == nil {
+		return nil, nilHandlerError(pattern)
+	}
+	pattern, err := r.expandFragments(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	// a template may require query params, i.e "/search?type=%s"
+	pathTemplate, queryTemplate, hasQuery := splitQueryTemplate(pattern)
+	if r.IgnoreTrailingSlash {
+		pathTemplate = trimTrailingSlash(pathTemplate)
+		pattern = pathTemplate
+		if hasQuery {
+			pattern += "?" + queryTemplate
+		}
+	}
+	// Convert the pattern from "/foo/bar/%s/baz/%s/qux" to a proper alphanumeric regex
+	opts := templateOptions{delimiter: r.Delimiter, foldStatic: r.LowercaseStaticSegments, classes: classes}
+	pathRegexStr, params := compileTemplateParams(pathTemplate, opts)
+	replacedRoute := "^" + pathRegexStr + "$"
+	fullPattern, err := regexp.Compile(replacedRoute)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template '%s': %w", pattern, err)
+	}
+	opts.structural = true
+	structuralPattern, err := regexp.Compile(makeRegexPatternStrWithOptions(pathTemplate, opts))
+	if err != nil {
+		return nil, fmt.Errorf("invalid template '%s': %w", pattern, err)
+	}
+	rt := &route{
+		template:   pattern,
+		pattern:    fullPattern,
+		structural: structuralPattern,
+		handler:    handler,
+		params:     params,
+	}
+	for _, param := range params {
+		if param.Class == dateParamClass {
+			rt.addValidator(param.Index, validateDateParam)
+		}
+	}
+	if hasQuery {
+		if rt.query, err = compileQueryTemplate(queryTemplate); err != nil {
+			return nil, fmt.Errorf("invalid template '%s': %w", pattern, err)
+		}
+	}
+	return rt, nil
+}
+
 type paramKey int
 
 func (r *customRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	if req.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	if r.AccessLogJSON {
+		r.serveWithAccessLog(w, req)
+		return
+	}
+	r.serve(w, req)
+}
+
+func (r *customRouter) serve(w http.ResponseWriter, req *http.Request) {
+	if r.AllowMethodOverride {
+		req = overrideMethod(req)
+	}
+	if err := checkPathEscapes(req); err != nil {
+		r.logger().Infof("Rejected %s %s: %v", req.Method, req.URL.RawPath, err)
+		r.writeError(w, http.StatusBadRequest, "Bad request: malformed percent-encoding in path")
+		return
+	}
+	if r.PathRewrite != nil {
+		req = rewritePath(req, r.PathRewrite)
+	}
+	if r.RejectDuplicateQueryKeys {
+		if key, ok := duplicateQueryKey(req); ok {
+			r.logger().Infof("Rejected %s %s with duplicate query param '%s'", req.Method, req.URL.Path, key)
+			r.writeError(w, http.StatusBadRequest, fmt.Sprintf("Bad request: duplicate query parameter '%s'", key))
+			return
+		}
+	}
+	if r.RejectEarlyData && req.Header.Get("Early-Data") == "1" && !isSafeMethod(req.Method) {
+		r.logger().Infof("Rejected early data %s %s", req.Method, req.URL.Path)
+		r.writeError(w, http.StatusTooEarly, "Too early")
+		return
+	}
+
+	path := req.URL.Path
+	if r.MaxPathLength > 0 && len(path) > r.MaxPathLength {
+		r.logger().Infof("Path of %d bytes exceeds the limit of %d", len(path), r.MaxPathLength)
+		r.requestTooLarge(w, req, http.StatusRequestURITooLong)
+		return
+	}
+
+	if r.IgnoreTrailingSlash {
+		path = trimTrailingSlash(path)
+	}
+
+	if !r.runGuards(w, req) {
 		return
 	}
 
-	for _, route := range r.routes {
-		matches := route.pattern.FindStringSubmatch(req.URL.Path)
+	// methods of the routes that matched the path but are registered for a different method
+	var allowedMethods []string
+	// the matches are only read while serving, so one pooled buffer is reused across routes and requests
+	buf := acquireSubmatches()
+	defer buf.release()
+	for _, route := range r.routeSnapshot() {
+		if !route.active() {
+			continue
+		}
+		matches, captured := route.matchInto(path, buf)
 		if matches != nil {
+			hostParams, hostNames, ok := route.matchHost(req.Host)
+			if !ok {
+				continue
+			}
+
+			queryParams, queryNames, ok := route.matchQuery(req)
+			if !ok {
+				continue
+			}
+
+			if route.accepts != nil && !route.accepts(req) {
+				continue
+			}
+
+			if !route.allowsMethod(req.Method) {
+				allowedMethods = append(allowedMethods, route.effectiveMethod())
+				continue
+			}
+
+			// routes registered for the same template with different cookies or languages compete for the request
+			route = r.selectVariant(route, req)
+			if route == nil {
+				continue
+			}
+
+			// first match is the full match, ignore it; params captured from the host then the query follow the path's
+			params := slices.Concat(route.canonicalParams(matches[1:]), hostParams, queryParams)
+			names := slices.Concat(route.pattern.SubexpNames()[1:], hostNames, queryNames)
+			if !route.paramsAllowed(params) {
+				// treated like any other mismatch so a later route may still serve the path
+				continue
+			}
+
 			// Store the path parameters in the request context
 			ctx := req.Context()
-			// first match is the full match, ignore it
-			for i, match := range matches[1:] {
+			for i, match := range params {
+				if i+1 < len(captured) && !captured[i+1] {
+					// the group didn't participate in the match, leave the param absent for getParamOK
+					continue
+				}
 				// Using the context to store params isn't ideal in plain stdlib,
 				// so here we're just attaching them to the request via a custom method
 				ctx = context.WithValue(ctx, paramKey(i+1), match) // Update ctx in each iteration
 			}
+			// names for '{name}' placeholders, positional params have an empty name
+			ctx = context.WithValue(ctx, paramNamesKey{}, names)
+			ctx = context.WithValue(ctx, methodKey{}, req.Method)
+			if len(route.aliases) > 0 {
+				ctx = context.WithValue(ctx, paramAliasesKey{}, route.aliases)
+			}
+			if len(route.queryKeys) > 0 {
+				ctx = context.WithValue(ctx, queryParamsKey{}, parseBoundQuery(req, route.queryKeys))
+			}
+			if route.remainder {
+				ctx = context.WithValue(ctx, remainderKey{}, matches[len(matches)-1])
+			}
+			ctx = context.WithValue(ctx, requestLoggerKey{}, r.routeLogger(route, req))
 
 			req = req.WithContext(ctx) // Update req once with the final context
-			route.handler(w, req)
+			recordMatchedTemplate(req, route.template)
+			route.touch(r)
+			if r.OnMatch != nil {
+				r.OnMatch(req, Matched)
+			}
+
+			if !route.hasExpectedParams(captured[1:]) {
+				r.logger().Errorf("Error: Expected %d capturing groups from path '%s' with pattern '%s'",
+					route.paramCount, path, route.pattern)
+				r.writeError(w, http.StatusInternalServerError, "Internal server error: Mismatched capturing groups")
+				return
+			}
+
+			if err := route.validateParams(params); err != nil {
+				r.writeError(w, http.StatusUnprocessableEntity, err.Error())
+				return
+			}
+
+			if len(route.produces) > 0 {
+				mediaType, ok := negotiateContentType(req.Header.Get("Accept"), route.produces)
+				if !ok {
+					r.writeError(w, http.StatusNotAcceptable, "Not acceptable")
+					return
+				}
+				req = req.WithContext(context.WithValue(req.Context(), contentTypeKey{}, mediaType))
+			}
+			if route.lang != "" {
+				req = req.WithContext(context.WithValue(req.Context(), languageKey{}, route.lang))
+			}
+
+			if route.budget > 0 {
+				ctx, cancel := context.WithTimeout(req.Context(), route.budget)
+				defer cancel()
+				req = req.WithContext(ctx)
+			}
+
+			r.logger().Debugf("Matched route '%s' for %s %s", route.template, req.Method, r.logPath(path, req))
+			rec := newStatusRecorder(w)
+			r.wrapHandler(route.handler)(rec, req)
+			if rec.Status() >= http.StatusInternalServerError {
+				r.logger().Errorf("Route '%s' responded %d for %s %s", route.template, rec.Status(), req.Method, r.logPath(path, req))
+			}
 			return
 		}
 	}
-	http.NotFound(w, req)
+
+	if len(allowedMethods) > 0 {
+		r.logger().Infof("Method %s not allowed for path '%s'", req.Method, r.logPath(path, req))
+		slices.Sort(allowedMethods)
+		w.Header().Set("Allow", strings.Join(slices.Compact(allowedMethods), ", "))
+		r.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	outcome := r.missOutcome(path)
+	r.logger().Infof("No route matched %s %s (%s)", req.Method, r.logPath(path, req), outcome)
+	if r.OnMiss != nil {
+		r.OnMiss(req, outcome)
+	}
+	if r.catchAll != nil {
+		r.serveCatchAll(w, req)
+		return
+	}
+	r.notFound(w, req)
 }
 
 func main() {
@@ -169,12 +579,14 @@ func main() {
 		log.Fatal(http.ListenAndServe(addr, cr))
 	} else {
 		// Method 2: Using http.ServeMux with a generalized regex handler
-		mux := http.NewServeMux()
 		routeTemplates := []string{
 			"/foo/bar/%s/baz/%s/qux",
 			"/api/v3/%s/%s",
 		}
-		registerRouteTemplates(mux, routeTemplates)
+		mux, err := buildServeMux(routeTemplates)
+		if err != nil {
+			log.Fatal(err)
+		}
 		log.Printf("Starting server with ServeMux on %s", addr)
 		log.Fatal(http.ListenAndServe(addr, mux))
 	}
@@ -188,6 +600,41 @@ func registerRouteTemplates(mux *http.ServeMux, routeTemplates []string) {
 	log.Printf("ServeMux handles %d routes %s", len(routeTemplates), strings.Join(routeTemplates, ", "))
 }
 
+// builds a ServeMux serving every template, sharing a dispatcher between templates with the same prefix.
+// Unlike registerRouteTemplates it returns an error instead of panicking when a template is empty,
+// duplicated, or can't be registered. Templates are duplicates when they register the same prefix with the
+// same pattern, so "/foo/%s" and "/foo/%s/" are both served but a repeated template would be shadowed.
+func buildServeMux(templates []string) (*http.ServeMux, error) {
+	mux := http.NewServeMux()
+	seen := make(map[string]bool, len(templates))
+	for _, template := range templates {
+		if template == "" {
+			return nil, fmt.Errorf("empty template")
+		}
+		registration := templateRegistration(template, makeRegexPatternStr(template))
+		if seen[registration] {
+			return nil, fmt.Errorf("template '%s' registered twice", template)
+		}
+		seen[registration] = true
+
+		if err := tryRegisterHandlerForPath(mux, template); err != nil {
+			return nil, err
+		}
+	}
+	return mux, nil
+}
+
+// like registerHandlerForPath but reports the panics of regexp.MustCompile and ServeMux.Handle as an error
+func tryRegisterHandlerForPath(mux *http.ServeMux, routeTemplateStr string) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("cannot register template '%s': %v", routeTemplateStr, recovered)
+		}
+	}()
+	registerHandlerForPath(mux, routeTemplateStr)
+	return nil
+}
+
 // registerHandlerForPath registers a handler for the given path template with the provided ServeMux
 func registerHandlerForPath(mux *http.ServeMux, routeTemplateStr string) {
 	// here, convert a path like "/foo/bar/%s/baz/%s/qux" to "/foo/bar/" to register just the 'prefix'
@@ -195,19 +642,40 @@ func registerHandlerForPath(mux *http.ServeMux, routeTemplateStr string) {
 	pathPrefixForMux := getPathPrefix(routeTemplateStr)
 	log.Printf("Registering handler for path prefix: '%s' with template %s\n", pathPrefixForMux, routeTemplateStr)
 
-	// create and register the handler for the given path
+	// create the handler for the given path
+	pathPattern := regexp.MustCompile(makeRegexPatternStr(routeTemplateStr))
 	handler := newPathRegexHandler(routeTemplateStr)
-	mux.HandleFunc(pathPrefixForMux, handler)
+
+	// ServeMux panics if the same prefix is registered twice, so templates sharing a prefix
+	// (i.e "/api/v3/%s/%s" and "/api/v3/%s/other") share a dispatcher that tries each of them
+	if dispatcher, ok := registeredDispatcher(mux, pathPrefixForMux); ok {
+		log.Printf("Path prefix '%s' already registered, adding template %s to its dispatcher\n", pathPrefixForMux, routeTemplateStr)
+		dispatcher.add(pathPattern, handler)
+		return
+	}
+
+	dispatcher := &prefixDispatcher{}
+	dispatcher.add(pathPattern, handler)
+	mux.Handle(pathPrefixForMux, dispatcher)
 }
 
-// return the prefix of the path pattern up to the first '%s' occurrence
+// return the prefix of the path pattern up to the first placeholder of any kind ('%s', '%d', '%t', '{name}'
+// and so on), cut back to the last '/' so it's a subtree pattern for ServeMux
 func getPathPrefix(pattern string) string {
-	// Find the first occurrence of '%s' and return the prefix up to that point
-	// i.e "/foo/bar/%s/baz/%s/qux" -> "/foo/bar/"
-	if idx := strings.Index(pattern, "%s"); idx != -1 {
-		return pattern[:idx]
-	}
+	// i.e "/foo/bar/%s/baz/%s/qux" -> "/foo/bar/" and "/geo/{coords:2}" -> "/geo/"
+	var prefix strings.Builder
+	hasParam := false
+	walkTemplate(pattern, templateOptions{}, func(static string) {
+		if !hasParam {
+			prefix.WriteString(static)
+		}
+	}, func(ParamInfo, string) {
+		hasParam = true
+	})
 	// allow for non-template routes as well
-	// If no '%s' found, return the whole pattern
-	return pattern
+	// If there's no placeholder, return the whole pattern
+	if !hasParam {
+		return pattern
+	}
+	return prefix.String()[:strings.LastIndex(prefix.String(), "/")+1]
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -325,6 +326,73 @@ func TestNewPathRegexHandler(t *testing.T) {
 	})
 }
 
+func TestNewPathRegexHandlerForMethod(t *testing.T) {
+	handler := newPathRegexHandlerForMethod("/items/%s", "post", http.MethodPut)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedAllow  string
+		expectedBody   string
+	}{
+		{name: "POST", method: http.MethodPost, path: "/items/abc", expectedStatus: http.StatusOK, expectedBody: "Parameter 1: abc\n"},
+		{name: "PUT", method: http.MethodPut, path: "/items/abc", expectedStatus: http.StatusOK, expectedBody: "Parameter 1: abc\n"},
+		{
+			name:           "method not allowed on a matching path",
+			method:         http.MethodGet,
+			path:           "/items/abc",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedAllow:  "POST, PUT",
+			expectedBody:   "Method not allowed\n",
+		},
+		{
+			name:           "path not matching with any method",
+			method:         http.MethodDelete,
+			path:           "/other/abc",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+			if allow := rr.Header().Get("Allow"); allow != tt.expectedAllow {
+				t.Errorf("unexpected Allow header: got %q want %q", allow, tt.expectedAllow)
+			}
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+
+	t.Run("GET wrapper", func(t *testing.T) {
+		handler := newPathRegexHandler("/items/%s")
+		req, err := http.NewRequest(http.MethodPost, "/items/abc", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusMethodNotAllowed || rr.Header().Get("Allow") != "GET" {
+			t.Errorf("got %d with Allow %q; want 405 with Allow GET", rr.Code, rr.Header().Get("Allow"))
+		}
+	})
+}
+
 func TestCustomRouter(t *testing.T) {
 	router := &customRouter{}
 	routeTemplateStr := "/foo/bar/%s/baz/%s/qux"
@@ -504,6 +572,26 @@ func TestGetPathPrefix(t *testing.T) {
 			pattern:  "",
 			expected: "",
 		},
+		{
+			name:     "numeric placeholder",
+			pattern:  "/orders/%d",
+			expected: "/orders/",
+		},
+		{
+			name:     "date placeholder",
+			pattern:  "/reports/%t/summary",
+			expected: "/reports/",
+		},
+		{
+			name:     "segment count placeholder",
+			pattern:  "/geo/{coords:2}",
+			expected: "/geo/",
+		},
+		{
+			name:     "placeholder within a segment",
+			pattern:  "/files/v%d/%s",
+			expected: "/files/",
+		},
 		{
 			name:     "pattern is just %s",
 			pattern:  "%s",
@@ -648,3 +736,46 @@ func TestCustomRouterOverlappingRoutes(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleTemplates(t *testing.T) {
+	router := &customRouter{}
+	templates := []string{
+		"/api/v3/%s/%s",
+		"/api/v3/%s/%s/version",
+		"/foo/bar/%s/baz/%s/qux",
+	}
+	router.HandleTemplates(templates, func(template string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "served by %s\n", template)
+		}
+	})
+
+	tests := []struct {
+		path         string
+		expectedBody string
+	}{
+		{path: "/api/v3/id1/id2", expectedBody: "served by /api/v3/%s/%s\n"},
+		{path: "/api/v3/id1/id2/version", expectedBody: "served by /api/v3/%s/%s/version\n"},
+		{path: "/foo/bar/a/baz/b/qux", expectedBody: "served by /foo/bar/%s/baz/%s/qux\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != http.StatusOK {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+			}
+
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
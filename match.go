@@ -0,0 +1,56 @@
+package main
+
+// key used by BenchmarkMatch for paths no route matched
+const noMatchKey = "<no match>"
+
+// Match returns the template of the first route serving method on path along with the captured path
+// params, without running any handler. Host and query requirements aren't considered as there's no request.
+func (r *customRouter) Match(method, path string) (string, []string, bool) {
+	if r.IgnoreTrailingSlash {
+		path = trimTrailingSlash(path)
+	}
+
+	for _, rt := range r.routeSnapshot() {
+		if !rt.active() {
+			continue
+		}
+		matches, _ := rt.match(path)
+		if matches != nil && rt.allowsMethod(method) {
+			return rt.template, matches[1:], true
+		}
+	}
+	return "", nil, false
+}
+
+// BenchmarkMatch runs the matcher over paths as GET requests without executing handlers, i.e for capacity
+// planning, returning how many paths each template matched; unmatched paths are counted under noMatchKey
+func (r *customRouter) BenchmarkMatch(paths []string) map[string]int {
+	counts := make(map[string]int)
+	for _, path := range paths {
+		template, _, ok := r.Match("GET", path)
+		if !ok {
+			template = noMatchKey
+		}
+		counts[template]++
+	}
+	return counts
+}
+
+// MatchAll returns the templates of every route that would serve method on path, in registration order,
+// i.e to find routes shadowed by an earlier, broader one. Like Match it ignores host and query requirements.
+func (r *customRouter) MatchAll(method, path string) []string {
+	if r.IgnoreTrailingSlash {
+		path = trimTrailingSlash(path)
+	}
+
+	var templates []string
+	for _, rt := range r.routeSnapshot() {
+		if !rt.active() {
+			continue
+		}
+		if matches, _ := rt.match(path); matches != nil && rt.allowsMethod(method) {
+			templates = append(templates, rt.template)
+		}
+	}
+	return templates
+}
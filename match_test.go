@@ -0,0 +1,140 @@
+package main
+
+import (
+	"maps"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	router := &customRouter{}
+	router.addTemplateRoutes([]string{"/api/v3/%s/%s", "/api/v3/%s/%s/version"})
+	router.HandleMethodFunc(http.MethodPost, "/items/%s", func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		name             string
+		method           string
+		path             string
+		expectedTemplate string
+		expectedParams   []string
+		expectedOK       bool
+	}{
+		{
+			name:             "first route",
+			method:           "GET",
+			path:             "/api/v3/id1/id2",
+			expectedTemplate: "/api/v3/%s/%s",
+			expectedParams:   []string{"id1", "id2"},
+			expectedOK:       true,
+		},
+		{
+			name:             "overlapping route",
+			method:           "GET",
+			path:             "/api/v3/id1/id2/version",
+			expectedTemplate: "/api/v3/%s/%s/version",
+			expectedParams:   []string{"id1", "id2"},
+			expectedOK:       true,
+		},
+		{
+			name:             "method specific route",
+			method:           "POST",
+			path:             "/items/abc",
+			expectedTemplate: "/items/%s",
+			expectedParams:   []string{"abc"},
+			expectedOK:       true,
+		},
+		{
+			name:       "wrong method",
+			method:     "GET",
+			path:       "/items/abc",
+			expectedOK: false,
+		},
+		{
+			name:       "no match",
+			method:     "GET",
+			path:       "/missing",
+			expectedOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, params, ok := router.Match(tt.method, tt.path)
+			if template != tt.expectedTemplate || ok != tt.expectedOK || !reflect.DeepEqual(params, tt.expectedParams) {
+				t.Errorf("Match(%q, %q) = %q, %q, %v; want %q, %q, %v", tt.method, tt.path,
+					template, params, ok, tt.expectedTemplate, tt.expectedParams, tt.expectedOK)
+			}
+		})
+	}
+}
+
+func TestBenchmarkMatch(t *testing.T) {
+	router := &customRouter{}
+	router.addTemplateRoutes([]string{"/api/v3/%s/%s", "/foo/bar/%s/baz/%s/qux"})
+
+	paths := []string{
+		"/api/v3/id1/id2",
+		"/api/v3/id3/id4",
+		"/foo/bar/a/baz/b/qux",
+		"/api/v3/id1",
+		"/missing",
+		"/api/v3/id1/id2/extra",
+	}
+
+	expected := map[string]int{
+		"/api/v3/%s/%s":          2,
+		"/foo/bar/%s/baz/%s/qux": 1,
+		noMatchKey:               3,
+	}
+
+	counts := router.BenchmarkMatch(paths)
+	if !maps.Equal(counts, expected) {
+		t.Errorf("BenchmarkMatch() = %v; want %v", counts, expected)
+	}
+}
+
+func TestMatchAll(t *testing.T) {
+	router := &customRouter{}
+	router.addTemplateRoutes([]string{"/files/{name:[a-z.]+}", "/files/%s", "/files/{doc:[a-z]+\\.txt}", "/other/%s"})
+	router.HandleMethodFunc(http.MethodPost, "/files/%s", func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		expected []string
+	}{
+		{
+			name:     "overlapping routes in registration order",
+			method:   "GET",
+			path:     "/files/abc",
+			expected: []string{"/files/{name:[a-z.]+}", "/files/%s"},
+		},
+		{
+			name:     "other method",
+			method:   "POST",
+			path:     "/files/abc",
+			expected: []string{"/files/%s"},
+		},
+		{
+			name:     "narrower routes shadowed",
+			method:   "GET",
+			path:     "/files/notes.txt",
+			expected: []string{"/files/{name:[a-z.]+}", "/files/{doc:[a-z]+\\.txt}"},
+		},
+		{
+			name:   "no match",
+			method: "GET",
+			path:   "/missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if templates := router.MatchAll(tt.method, tt.path); !reflect.DeepEqual(templates, tt.expected) {
+				t.Errorf("MatchAll(%q, %q) = %q; want %q", tt.method, tt.path, templates, tt.expected)
+			}
+		})
+	}
+}
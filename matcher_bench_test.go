@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// route table the matcher benchmarks run against, shaped like a typical REST API
+var benchRouteTemplates = []string{
+	"/health",
+	"/metrics",
+	"/api/v1/status",
+	"/api/v1/users/%s",
+	"/api/v1/users/%s/orders",
+	"/api/v1/users/%s/orders/%s",
+	"/api/v1/orders/%s/items/%s/refunds/%s",
+	"/api/v1/products/%s",
+	"/api/v1/products/%s/reviews/%s",
+	"/static/assets/app",
+}
+
+// request paths per scenario, each matching a route at a different depth of the table
+var benchScenarios = []struct {
+	name string
+	path string
+}{
+	{name: "static", path: "/static/assets/app"},
+	{name: "single param", path: "/api/v1/products/p42"},
+	{name: "multi param", path: "/api/v1/orders/o1/items/i2/refunds/r3"},
+	{name: "miss", path: "/api/v2/unknown/path"},
+}
+
+// a matcher finds the template serving a path and its params, reporting false when none does
+type benchMatcher interface {
+	match(path string) (string, []string, bool)
+}
+
+// the router's approach: one compiled regex per template, tried in order
+type regexMatcher struct {
+	templates []string
+	patterns  []*regexp.Regexp
+}
+
+func newRegexMatcher(templates []string) *regexMatcher {
+	m := &regexMatcher{templates: templates}
+	for _, template := range templates {
+		m.patterns = append(m.patterns, regexp.MustCompile(makeRegexPatternStr(template)))
+	}
+	return m
+}
+
+func (m *regexMatcher) match(path string) (string, []string, bool) {
+	for i, pattern := range m.patterns {
+		if matches := pattern.FindStringSubmatch(path); matches != nil {
+			return m.templates[i], matches[1:], true
+		}
+	}
+	return "", nil, false
+}
+
+// the alternative: templates pre-split into segments compared one by one, '%s' accepting any alphanumeric segment
+type segmentMatcher struct {
+	templates []string
+	segments  [][]string
+}
+
+func newSegmentMatcher(templates []string) *segmentMatcher {
+	m := &segmentMatcher{templates: templates}
+	for _, template := range templates {
+		m.segments = append(m.segments, strings.Split(template, "/"))
+	}
+	return m
+}
+
+func (m *segmentMatcher) match(path string) (string, []string, bool) {
+	pathSegments := strings.Split(path, "/")
+	for i, segments := range m.segments {
+		if len(segments) != len(pathSegments) {
+			continue
+		}
+		var params []string
+		matched := true
+		for j, segment := range segments {
+			if segment == "%s" {
+				if !isAlphanumeric(pathSegments[j]) {
+					matched = false
+					break
+				}
+				params = append(params, pathSegments[j])
+			} else if segment != pathSegments[j] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return m.templates[i], params, true
+		}
+	}
+	return "", nil, false
+}
+
+// same class as '%s', [a-zA-Z0-9]+
+func isAlphanumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// the matchers compared by BenchmarkMatchers; add new approaches here as they land
+func benchMatchers() []struct {
+	name    string
+	matcher benchMatcher
+} {
+	return []struct {
+		name    string
+		matcher benchMatcher
+	}{
+		{name: "regex", matcher: newRegexMatcher(benchRouteTemplates)},
+		{name: "split", matcher: newSegmentMatcher(benchRouteTemplates)},
+	}
+}
+
+// the numbers are only meaningful if every matcher agrees on the result
+func TestBenchMatchersAgree(t *testing.T) {
+	matchers := benchMatchers()
+	reference := matchers[0]
+	for _, scenario := range benchScenarios {
+		wantTemplate, wantParams, wantOK := reference.matcher.match(scenario.path)
+		for _, m := range matchers[1:] {
+			template, params, ok := m.matcher.match(scenario.path)
+			if template != wantTemplate || !slices.Equal(params, wantParams) || ok != wantOK {
+				t.Errorf("%s matcher on %q: got %q %q %v, %s got %q %q %v", m.name, scenario.path,
+					template, params, ok, reference.name, wantTemplate, wantParams, wantOK)
+			}
+		}
+	}
+}
+
+// compares matching strategies per scenario, run with:
+//
+//	go test -run '^$' -bench BenchmarkMatchers -benchmem
+func BenchmarkMatchers(b *testing.B) {
+	for _, m := range benchMatchers() {
+		for _, scenario := range benchScenarios {
+			b.Run(fmt.Sprintf("%s/%s", m.name, scenario.name), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					m.matcher.match(scenario.path)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MatchErrorKind tells why a path didn't match a template
+type MatchErrorKind int
+
+const (
+	// the template doesn't compile
+	MatchErrorInvalidTemplate MatchErrorKind = iota
+	// the path has a different shape: segment count or static text differ
+	MatchErrorStructural
+	// the path has the template's shape but a param's value isn't in its class
+	MatchErrorClass
+)
+
+func (k MatchErrorKind) String() string {
+	switch k {
+	case MatchErrorInvalidTemplate:
+		return "invalid template"
+	case MatchErrorStructural:
+		return "structural mismatch"
+	case MatchErrorClass:
+		return "class mismatch"
+	default:
+		return fmt.Sprintf("MatchErrorKind(%d)", int(k))
+	}
+}
+
+// MatchError describes why a path doesn't match a template, see ExtractParamsErr
+type MatchError struct {
+	Template string
+	Path     string
+	Kind     MatchErrorKind
+	// 1-indexed '/' separated segment of the path that failed, i.e 2 for "abc!" in "/users/abc!";
+	// 0 when it can't be pinned to one segment
+	Segment int
+	// the failing path segment, empty if the path has fewer segments than the template
+	Value string
+	// for a class mismatch, the regex class the value failed
+	Class string
+	// for an invalid template, the compile error
+	Err error
+}
+
+func (e *MatchError) Error() string {
+	switch {
+	case e.Kind == MatchErrorInvalidTemplate:
+		return fmt.Sprintf("invalid template '%s': %v", e.Template, e.Err)
+	case e.Segment == 0:
+		return fmt.Sprintf("path '%s' doesn't match template '%s': %s", e.Path, e.Template, e.Kind)
+	case e.Kind == MatchErrorClass:
+		return fmt.Sprintf("path '%s' doesn't match template '%s': segment %d '%s' doesn't match class %s",
+			e.Path, e.Template, e.Segment, e.Value, e.Class)
+	default:
+		return fmt.Sprintf("path '%s' doesn't match template '%s': %s at segment %d",
+			e.Path, e.Template, e.Kind, e.Segment)
+	}
+}
+
+func (e *MatchError) Unwrap() error {
+	return e.Err
+}
+
+// placeholders spanning several segments, which like an optional version prefix rule out comparing
+// segment by segment
+var multiSegmentPlaceholderRegex = regexp.MustCompile(`\{[a-zA-Z_][a-zA-Z0-9_]*:[0-9]+\}`)
+
+// works out why path doesn't match template, which must compile: segments are compared one by one,
+// first ignoring param classes to find structural differences and then with them
+func diagnoseMismatch(template, path string) *MatchError {
+	matchErr := &MatchError{Template: template, Path: path, Kind: MatchErrorStructural}
+
+	if multiSegmentPlaceholderRegex.MatchString(template) || strings.HasPrefix(template, optionalVersionPrefix) {
+		structural, err := regexp.Compile(makeRegexPatternStrWithOptions(template, templateOptions{structural: true}))
+		if err == nil && structural.MatchString(path) {
+			matchErr.Kind = MatchErrorClass
+		}
+		return matchErr
+	}
+
+	templateSegments := splitTemplateSegments(template)
+	pathSegments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i, templateSegment := range templateSegments {
+		if i >= len(pathSegments) {
+			matchErr.Segment = i + 1
+			return matchErr
+		}
+
+		structural, err := regexp.Compile("^" + templateSegment.structural + "$")
+		if err != nil {
+			// the segment's regex only compiles as part of the whole template, so don't pin it down
+			return matchErr
+		}
+		if !structural.MatchString(pathSegments[i]) {
+			matchErr.Segment, matchErr.Value = i+1, pathSegments[i]
+			return matchErr
+		}
+
+		exact, err := regexp.Compile("^" + templateSegment.regex + "$")
+		if err != nil {
+			return matchErr
+		}
+		if !exact.MatchString(pathSegments[i]) {
+			matchErr.Kind, matchErr.Segment, matchErr.Value = MatchErrorClass, i+1, pathSegments[i]
+			if len(templateSegment.params) == 1 {
+				matchErr.Class = templateSegment.params[0].Class
+			}
+			return matchErr
+		}
+	}
+
+	// every template segment matched so the path has extra ones
+	matchErr.Segment = len(templateSegments) + 1
+	if matchErr.Segment <= len(pathSegments) {
+		matchErr.Value = pathSegments[matchErr.Segment-1]
+	}
+	return matchErr
+}
+
+// the regexes matching a single '/' separated segment of a template
+type templateSegment struct {
+	structural string // matches any value for each param, ignoring its class
+	regex      string
+	params     []ParamInfo
+}
+
+// splits a template into its '/' separated segments, only splitting the static text so a custom class
+// containing '/', i.e "{id:[^/]+}", stays in one piece; the leading '/' doesn't start a segment
+func splitTemplateSegments(template string) []templateSegment {
+	segments := []templateSegment{{}}
+	structuralGroup := "(" + templateOptions{structural: true}.paramClass() + ")"
+	walkTemplate(template, templateOptions{}, func(static string) {
+		for i, part := range strings.Split(static, "/") {
+			if i > 0 {
+				segments = append(segments, templateSegment{})
+			}
+			last := &segments[len(segments)-1]
+			last.structural += part
+			last.regex += part
+		}
+	}, func(param ParamInfo, group string) {
+		last := &segments[len(segments)-1]
+		last.structural += structuralGroup
+		last.regex += group
+		last.params = append(last.params, param)
+	})
+
+	if strings.HasPrefix(template, "/") {
+		return segments[1:]
+	}
+	return segments
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExtractParamsErr(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		path     string
+		expected MatchError
+	}{
+		{
+			name:     "class failure",
+			template: "/users/%s/orders/%d",
+			path:     "/users/u1/orders/abc",
+			expected: MatchError{Kind: MatchErrorClass, Segment: 4, Value: "abc", Class: "[0-9]+"},
+		},
+		{
+			name:     "class failure in a named param",
+			template: "/users/{user}",
+			path:     "/users/u-1",
+			expected: MatchError{Kind: MatchErrorClass, Segment: 2, Value: "u-1", Class: "[a-zA-Z0-9]+"},
+		},
+		{
+			name:     "static segment differs",
+			template: "/users/%s/orders",
+			path:     "/users/u1/invoices",
+			expected: MatchError{Kind: MatchErrorStructural, Segment: 3, Value: "invoices"},
+		},
+		{
+			name:     "too few segments",
+			template: "/users/%s/orders/%s",
+			path:     "/users/u1",
+			expected: MatchError{Kind: MatchErrorStructural, Segment: 3},
+		},
+		{
+			name:     "too many segments",
+			template: "/users/%s",
+			path:     "/users/u1/extra",
+			expected: MatchError{Kind: MatchErrorStructural, Segment: 3, Value: "extra"},
+		},
+		{
+			name:     "custom class containing a slash",
+			template: "/users/{id:[^/]+}",
+			path:     "/users/a/b",
+			expected: MatchError{Kind: MatchErrorStructural, Segment: 3, Value: "b"},
+		},
+		{
+			name:     "class failure in a custom class containing a slash",
+			template: "/files/{name:[^/.]+}/raw",
+			path:     "/files/a.b/raw",
+			expected: MatchError{Kind: MatchErrorClass, Segment: 2, Value: "a.b", Class: "[^/.]+"},
+		},
+		{
+			name:     "multi-segment placeholder class failure",
+			template: "/geo/{coords:2}/%d",
+			path:     "/geo/45.0/-93.0/x",
+			expected: MatchError{Kind: MatchErrorClass},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := ExtractParamsErr(tt.template, tt.path)
+			if params != nil {
+				t.Errorf("expected no params, got %q", params)
+			}
+
+			var matchErr *MatchError
+			if !errors.As(err, &matchErr) {
+				t.Fatalf("expected a *MatchError, got %v", err)
+			}
+			if matchErr.Template != tt.template || matchErr.Path != tt.path {
+				t.Errorf("unexpected template/path: got %q %q", matchErr.Template, matchErr.Path)
+			}
+			if matchErr.Kind != tt.expected.Kind || matchErr.Segment != tt.expected.Segment ||
+				matchErr.Value != tt.expected.Value || matchErr.Class != tt.expected.Class {
+				t.Errorf("got kind %v segment %d value %q class %q; want kind %v segment %d value %q class %q",
+					matchErr.Kind, matchErr.Segment, matchErr.Value, matchErr.Class,
+					tt.expected.Kind, tt.expected.Segment, tt.expected.Value, tt.expected.Class)
+			}
+			if matchErr.Error() == "" {
+				t.Error("expected a message")
+			}
+		})
+	}
+
+	t.Run("custom class containing a slash doesn't panic the bool API", func(t *testing.T) {
+		if params, ok := ExtractParams("/users/{id:[^/]+}", "/users/a/b"); ok {
+			t.Errorf("expected no match, got %q", params)
+		}
+	})
+
+	t.Run("match", func(t *testing.T) {
+		params, err := ExtractParamsErr("/users/%s", "/users/u1")
+		if err != nil || len(params) != 1 || params[0] != "u1" {
+			t.Errorf("got %q, %v", params, err)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Merge copies the routes registered on other into r, i.e. to compose route tables built independently.
+// If any of other's templates registers the same prefix and pattern as one of r's for the same method
+// and host, like buildServeMux rejects, nothing is copied and an error listing the conflicting patterns
+// is returned. The routes are copied, so settings applied to either router afterwards stay with it.
+// Only routes are merged; middleware configured on other is not carried over to r.
+func (r *customRouter) Merge(other *customRouter) error {
+	if other == nil {
+		return nil
+	}
+
+	incomingRoutes := other.routeSnapshot()
+	r.routesMu.Lock()
+	defer r.routesMu.Unlock()
+
+	var conflicts []string
+	for _, incoming := range incomingRoutes {
+		for _, existing := range r.routes {
+			if !sameMethodAndHost(existing, incoming) {
+				continue
+			}
+			if templateRegistration(existing.template, existing.pattern.String()) ==
+				templateRegistration(incoming.template, incoming.pattern.String()) {
+				conflicts = append(conflicts, incoming.pattern.String())
+				break
+			}
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return fmt.Errorf("cannot merge routers: %d conflicting pattern(s): %s",
+			len(conflicts), strings.Join(conflicts, ", "))
+	}
+
+	for _, incoming := range incomingRoutes {
+		// copied so settings applied to one router after merging don't show up in the other
+		rt := incoming.clone()
+		// ticks from other's clock mean nothing here, merged routes count as just registered
+		rt.touch(r)
+		markVariants(rt, r.routes)
+		r.routes = append(r.routes, rt)
+	}
+	r.evictRoutes()
+	return nil
+}
+
+// reports whether two routes serve the same method on the same host, i.e so registering both for a path
+// would shadow one of them
+func sameMethodAndHost(a, b *route) bool {
+	if a.effectiveMethod() != b.effectiveMethod() {
+		return false
+	}
+	hostPattern := func(rt *route) string {
+		if rt.host == nil {
+			return ""
+		}
+		return rt.host.String()
+	}
+	return hostPattern(a) == hostPattern(b)
+}
+
+// returns a copy of rt whose settings can be changed without affecting rt. Allow sets belong to the caller,
+// who updates them while serving, so they stay shared; the LRU tick and variant flag are left for the
+// router the copy is added to.
+func (rt *route) clone() *route {
+	return &route{
+		template:   rt.template,
+		pattern:    rt.pattern,
+		structural: rt.structural,
+		handler:    rt.handler,
+		method:     rt.method,
+		host:       rt.host,
+		queryKeys:  slices.Clone(rt.queryKeys),
+		aliases:    maps.Clone(rt.aliases),
+		query:      slices.Clone(rt.query),
+		produces:   slices.Clone(rt.produces),
+		lang:       rt.lang,
+		budget:     rt.budget,
+		cookie:     rt.cookie,
+		params:     slices.Clone(rt.params),
+		remainder:  rt.remainder,
+		enabled:    rt.enabled,
+		allowSets:  maps.Clone(rt.allowSets),
+		paramCount: rt.paramCount,
+		validators: maps.Clone(rt.validators),
+		accepts:    rt.accepts,
+	}
+}
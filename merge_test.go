@@ -0,0 +1,140 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCustomRouterMerge(t *testing.T) {
+	t.Run("non-conflicting routes", func(t *testing.T) {
+		router := &customRouter{}
+		router.addTemplateRoutes([]string{"/foo/bar/%s/baz/%s/qux"})
+
+		other := &customRouter{}
+		other.addTemplateRoutes([]string{"/api/v3/%s/%s", "/api/v3/%s/%s/version"})
+
+		if err := router.Merge(other); err != nil {
+			t.Fatalf("unexpected error merging routers: %v", err)
+		}
+
+		if len(router.routes) != 3 {
+			t.Fatalf("expected 3 routes after merge, got %d", len(router.routes))
+		}
+
+		paths := []string{
+			"/foo/bar/alpha/baz/beta/qux",
+			"/api/v3/id1/id2",
+			"/api/v3/id1/id2/version",
+		}
+		for _, path := range paths {
+			req, err := http.NewRequest("GET", path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != http.StatusOK {
+				t.Errorf("handler returned wrong status code for %s: got %v want %v", path, status, http.StatusOK)
+			}
+		}
+	})
+
+	t.Run("conflicting routes", func(t *testing.T) {
+		router := &customRouter{}
+		router.addTemplateRoutes([]string{"/api/v3/%s/%s", "/foo/bar/%s/baz/%s/qux"})
+
+		other := &customRouter{}
+		other.addTemplateRoutes([]string{"/api/v3/%s/%s/version", "/api/v3/%s/%s"})
+
+		err := router.Merge(other)
+		if err == nil {
+			t.Fatal("expected an error merging conflicting routers, got nil")
+		}
+
+		if !strings.Contains(err.Error(), makeRegexPatternStr("/api/v3/%s/%s")) {
+			t.Errorf("error does not list the conflicting pattern: %v", err)
+		}
+
+		if len(router.routes) != 2 {
+			t.Errorf("expected routes to be left untouched on conflict, got %d routes", len(router.routes))
+		}
+	})
+
+	t.Run("same template for different methods", func(t *testing.T) {
+		router := &customRouter{}
+		router.HandleMethodFunc(http.MethodGet, "/api/v3/%s/%s", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("get"))
+		})
+
+		other := &customRouter{}
+		other.HandleMethodFunc(http.MethodPost, "/api/v3/%s/%s", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("post"))
+		})
+
+		if err := router.Merge(other); err != nil {
+			t.Fatalf("unexpected error merging routers: %v", err)
+		}
+
+		for _, method := range []string{http.MethodGet, http.MethodPost} {
+			req, err := http.NewRequest(method, "/api/v3/id1/id2", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != http.StatusOK {
+				t.Errorf("handler returned wrong status code for %s: got %v want %v", method, status, http.StatusOK)
+			}
+			if want := strings.ToLower(method); rr.Body.String() != want {
+				t.Errorf("handler returned unexpected body for %s: got %q want %q", method, rr.Body.String(), want)
+			}
+		}
+	})
+
+	t.Run("same template for different hosts", func(t *testing.T) {
+		router := &customRouter{}
+		router.HandleHost("a.example.com", "/api/v3/%s/%s", func(w http.ResponseWriter, r *http.Request) {})
+
+		other := &customRouter{}
+		other.HandleHost("b.example.com", "/api/v3/%s/%s", func(w http.ResponseWriter, r *http.Request) {})
+
+		if err := router.Merge(other); err != nil {
+			t.Fatalf("unexpected error merging routers: %v", err)
+		}
+		if len(router.routes) != 2 {
+			t.Errorf("expected 2 routes after merge, got %d", len(router.routes))
+		}
+	})
+}
+
+func TestMergeCopiesRoutes(t *testing.T) {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	router := &customRouter{}
+	other := &customRouter{}
+	other.HandleFunc("/users/{userID}", noop)
+
+	if err := router.Merge(other); err != nil {
+		t.Fatal(err)
+	}
+	if err := router.AliasParam("/users/{userID}", "uid", "userID"); err != nil {
+		t.Fatal(err)
+	}
+	if err := router.BindQuery("/users/{userID}", []string{"fields"}); err != nil {
+		t.Fatal(err)
+	}
+
+	merged, original := router.routeSnapshot()[0], other.routeSnapshot()[0]
+	if merged == original {
+		t.Fatal("expected the merged route to be a copy")
+	}
+	if original.aliases != nil || original.queryKeys != nil {
+		t.Errorf("settings applied after merging leaked into the original: aliases %v, query keys %v",
+			original.aliases, original.queryKeys)
+	}
+}
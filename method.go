@@ -0,0 +1,79 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+)
+
+// methods a POST may be overridden to; overriding to a safe method like GET is never allowed
+var overridableMethods = map[string]bool{
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
+// reports whether the method is safe, i.e doesn't change server state, per RFC 9110
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// method of routes serving every method, i.e redirects
+const anyMethod = "*"
+
+// reserved param name the matched request's method can be read under, see getMethod
+const methodParamName = "method"
+
+// context key for the method the request was routed with, after any override
+type methodKey struct{}
+
+// returns the method the request was routed with, i.e for handlers of routes serving several methods;
+// also available as getParamByName(r, "method") unless the template has a param of that name
+func getMethod(r *http.Request) string {
+	method, _ := r.Context().Value(methodKey{}).(string)
+	return method
+}
+
+// reports whether the route serves the given method; routes without an explicit method serve GET
+func (rt *route) allowsMethod(method string) bool {
+	switch rt.method {
+	case "":
+		return method == http.MethodGet
+	case anyMethod:
+		return true
+	}
+	return rt.method == method
+}
+
+// returns the method the route serves, GET for routes registered without one
+func (rt *route) effectiveMethod() string {
+	if rt.method == "" {
+		return http.MethodGet
+	}
+	return rt.method
+}
+
+// returns a copy of a POST request using the method from the 'X-HTTP-Method-Override' header
+// (or failing that the '_method' query param); any other request is returned unchanged
+func overrideMethod(req *http.Request) *http.Request {
+	if req.Method != http.MethodPost {
+		return req
+	}
+
+	override := req.Header.Get("X-HTTP-Method-Override")
+	if override == "" {
+		override = req.URL.Query().Get("_method")
+	}
+
+	override = strings.ToUpper(override)
+	if !overridableMethods[override] {
+		return req
+	}
+
+	overridden := req.Clone(req.Context())
+	overridden.Method = override
+	return overridden
+}
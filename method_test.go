@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAllowMethodOverride(t *testing.T) {
+	router := &customRouter{AllowMethodOverride: true}
+	router.HandleMethodFunc(http.MethodDelete, "/items/%s", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "deleted %s via %s\n", getParam(r, 1), r.Method)
+	})
+	router.HandleFunc("/items/%s", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "fetched %s via %s\n", getParam(r, 1), r.Method)
+	})
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		header         string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "POST overridden to DELETE via header",
+			method:         http.MethodPost,
+			path:           "/items/abc",
+			header:         "DELETE",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "deleted abc via DELETE\n",
+		},
+		{
+			name:           "POST overridden to DELETE via query",
+			method:         http.MethodPost,
+			path:           "/items/abc?_method=delete",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "deleted abc via DELETE\n",
+		},
+		{
+			name:           "GET cannot be overridden",
+			method:         http.MethodGet,
+			path:           "/items/abc",
+			header:         "DELETE",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "fetched abc via GET\n",
+		},
+		{
+			name:           "POST cannot be overridden to GET",
+			method:         http.MethodPost,
+			path:           "/items/abc",
+			header:         "GET",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedBody:   "Method not allowed\n",
+		},
+		{
+			name:           "POST without override",
+			method:         http.MethodPost,
+			path:           "/items/abc",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedBody:   "Method not allowed\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.header != "" {
+				req.Header.Set("X-HTTP-Method-Override", tt.header)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v",
+					status, tt.expectedStatus)
+			}
+
+			if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %q want %q",
+					rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestMethodOverrideDisabled(t *testing.T) {
+	router := &customRouter{}
+	router.HandleMethodFunc(http.MethodDelete, "/items/%s", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "deleted")
+	})
+
+	req, err := http.NewRequest(http.MethodPost, "/items/abc", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-HTTP-Method-Override", "DELETE")
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusMethodNotAllowed {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestGetMethod(t *testing.T) {
+	router := &customRouter{AllowMethodOverride: true}
+	router.HandleMethodFunc(anyMethod, "/items/%s", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s", getMethod(r), getParamByName(r, "method"))
+	})
+	router.HandleMethodFunc(anyMethod, "/calls/{method}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s", getMethod(r), getParamByName(r, "method"))
+	})
+
+	tests := []struct {
+		name         string
+		method       string
+		override     string
+		path         string
+		expectedBody string
+	}{
+		{name: "GET", method: http.MethodGet, path: "/items/abc", expectedBody: "GET GET"},
+		{name: "POST", method: http.MethodPost, path: "/items/abc", expectedBody: "POST POST"},
+		{name: "overridden method", method: http.MethodPost, override: http.MethodDelete, path: "/items/abc", expectedBody: "DELETE DELETE"},
+		{name: "template param named method wins", method: http.MethodPost, path: "/calls/lookup", expectedBody: "POST lookup"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.override != "" {
+				req.Header.Set("X-HTTP-Method-Override", tt.override)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestHandleMethodFunc(t *testing.T) {
+	router := &customRouter{}
+	router.HandleMethodFunc(http.MethodGet, "/api/v3/%s/%s", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "get %s %s", getParam(r, 1), getParam(r, 2))
+	})
+	router.HandleMethodFunc("post", "/api/v3/%s/%s", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "post %s %s", getParam(r, 1), getParam(r, 2))
+	})
+	// HandleFunc registers GET routes as it always has
+	router.HandleFunc("/legacy/%s", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "legacy %s", getParam(r, 1))
+	})
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedBody   string
+		expectedAllow  string
+	}{
+		{name: "GET route", method: http.MethodGet, path: "/api/v3/a/b", expectedStatus: http.StatusOK, expectedBody: "get a b"},
+		{name: "POST route", method: http.MethodPost, path: "/api/v3/a/b", expectedStatus: http.StatusOK, expectedBody: "post a b"},
+		{
+			name:           "unregistered method lists the registered ones",
+			method:         http.MethodDelete,
+			path:           "/api/v3/a/b",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedBody:   "Method not allowed\n",
+			expectedAllow:  "GET, POST",
+		},
+		{name: "shim GET route", method: http.MethodGet, path: "/legacy/x", expectedStatus: http.StatusOK, expectedBody: "legacy x"},
+		{
+			name:           "shim route rejects other methods",
+			method:         http.MethodPut,
+			path:           "/legacy/x",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedBody:   "Method not allowed\n",
+			expectedAllow:  "GET",
+		},
+		{name: "unmatched path", method: http.MethodDelete, path: "/missing", expectedStatus: http.StatusNotFound, expectedBody: "404 page not found\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+			if allow := rr.Header().Get("Allow"); allow != tt.expectedAllow {
+				t.Errorf("unexpected Allow header: got %q want %q", allow, tt.expectedAllow)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package main
+
+import "net/http"
+
+// adds middleware wrapping the handler of every matched route; it runs after the route's own checks
+// (method, validation, negotiation) and applies to routes registered before or after it
+func (r *customRouter) use(mw func(http.HandlerFunc) http.HandlerFunc) {
+	r.middleware = append(r.middleware, mw)
+}
+
+// wraps handler in the router's middleware, the first added being outermost
+func (r *customRouter) wrapHandler(handler http.HandlerFunc) http.HandlerFunc {
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		handler = r.middleware[i](handler)
+	}
+	return handler
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// context key for the media type chosen by content negotiation
+type contentTypeKey struct{}
+
+// HandleFuncProduces registers a route that can only respond with the given media types, i.e
+// "application/json". Requests whose 'Accept' header matches none of them get a 406 Not Acceptable;
+// otherwise the chosen type is available to the handler via negotiatedContentType.
+func (r *customRouter) HandleFuncProduces(pattern string, mediaTypes []string, handler http.HandlerFunc) {
+	r.handle(pattern, handler, func(rt *route) {
+		rt.produces = mediaTypes
+	})
+}
+
+// returns the media type chosen for the request by content negotiation, or "" if the route didn't declare any
+func negotiatedContentType(r *http.Request) string {
+	mediaType, _ := r.Context().Value(contentTypeKey{}).(string)
+	return mediaType
+}
+
+// picks the offered media type the 'Accept' header prefers, reporting false if none are acceptable.
+// A missing header accepts anything, so the first offer is used; ties go to the earlier offer.
+func negotiateContentType(accept string, offers []string) (string, bool) {
+	if len(offers) == 0 {
+		return "", false
+	}
+	if strings.TrimSpace(accept) == "" {
+		return offers[0], true
+	}
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		if q := acceptQuality(accept, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best, bestQ > 0
+}
+
+// returns the quality the 'Accept' header gives a media type, using its most specific matching range
+func acceptQuality(accept, mediaType string) float64 {
+	mediaType = strings.ToLower(mediaType)
+	offerType, _, _ := strings.Cut(mediaType, "/")
+
+	return headerQuality(accept, func(accepted string) int {
+		acceptedType, acceptedSubtype, _ := strings.Cut(accepted, "/")
+		// exact matches beat "type/*" which beats "*/*"
+		switch {
+		case accepted == mediaType:
+			return 2
+		case acceptedSubtype == "*" && acceptedType == offerType:
+			return 1
+		case accepted == "*/*":
+			return 0
+		}
+		return -1
+	})
+}
+
+// returns the q-value of the most specific element of a comma separated header like 'Accept' matching
+// an offer. specificity ranks an element's lowercased range against the offer,
+// higher being more specific and -1 meaning it doesn't match; ties go to the earlier element. 0 if no
+// element matches, 1 for a matching element without a q param.
+func headerQuality(header string, specificity func(accepted string) int) float64 {
+	quality, best := 0.0, -1
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		s := specificity(strings.ToLower(strings.TrimSpace(fields[0])))
+		if s < 0 || s <= best {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(key, "q") {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		quality, best = q, s
+	}
+	return quality
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNegotiateContentType(t *testing.T) {
+	offers := []string{"application/json", "text/plain"}
+
+	tests := []struct {
+		name       string
+		accept     string
+		expected   string
+		expectedOK bool
+	}{
+		{name: "no header", accept: "", expected: "application/json", expectedOK: true},
+		{name: "exact match", accept: "text/plain", expected: "text/plain", expectedOK: true},
+		{name: "wildcard", accept: "*/*", expected: "application/json", expectedOK: true},
+		{name: "type wildcard", accept: "text/*", expected: "text/plain", expectedOK: true},
+		{name: "quality preference", accept: "application/json;q=0.5, text/plain", expected: "text/plain", expectedOK: true},
+		{name: "specific range overrides wildcard", accept: "*/*, application/json;q=0", expected: "text/plain", expectedOK: true},
+		{name: "nothing acceptable", accept: "application/xml", expectedOK: false},
+		{name: "explicitly refused", accept: "application/json;q=0, text/plain;q=0", expectedOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := negotiateContentType(tt.accept, offers)
+			if ok != tt.expectedOK || result != tt.expected {
+				t.Errorf("negotiateContentType(%q) = %q, %v; want %q, %v", tt.accept, result, ok, tt.expected, tt.expectedOK)
+			}
+		})
+	}
+}
+
+func TestHandleFuncProduces(t *testing.T) {
+	router := &customRouter{}
+	router.HandleFuncProduces("/users/%s", []string{"application/json"}, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", negotiatedContentType(r))
+		fmt.Fprintf(w, `{"user":%q}`+"\n", getParam(r, 1))
+	})
+
+	tests := []struct {
+		name           string
+		accept         string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "JSON accepted",
+			accept:         "application/json",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"user":"u1"}` + "\n",
+		},
+		{
+			name:           "XML only against a JSON-only route",
+			accept:         "application/xml",
+			expectedStatus: http.StatusNotAcceptable,
+			expectedBody:   "Not acceptable\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/users/u1", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Accept", tt.accept)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
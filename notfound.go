@@ -0,0 +1,60 @@
+package main
+
+import (
+	"maps"
+	"net/http"
+)
+
+// AddNotFoundFallback appends a handler to the chain tried, in order, when no route matches, i.e a static
+// file server followed by a custom 404 page. A fallback declines a request by responding 404 (which is
+// discarded) or by not writing a response at all; the next fallback then runs, ending in the default 404.
+func (r *customRouter) AddNotFoundFallback(h http.Handler) {
+	r.notFoundFallbacks = append(r.notFoundFallbacks, h)
+}
+
+// runs the not-found fallbacks until one handles the request, otherwise responds with the default 404
+func (r *customRouter) notFound(w http.ResponseWriter, req *http.Request) {
+	for _, fallback := range r.notFoundFallbacks {
+		// restore headers set by a fallback that declines so they don't leak into the next response
+		headers := maps.Clone(w.Header())
+		fw := &fallbackWriter{ResponseWriter: w}
+		fallback.ServeHTTP(fw, req)
+		if fw.started {
+			return
+		}
+
+		clear(w.Header())
+		maps.Copy(w.Header(), headers)
+	}
+	r.writeError(w, http.StatusNotFound, "404 page not found")
+}
+
+// wraps a fallback's http.ResponseWriter, swallowing a 404 response so the next fallback can run
+type fallbackWriter struct {
+	http.ResponseWriter
+	started  bool // a response other than 404 was written through to the client
+	declined bool // the fallback responded 404
+}
+
+func (fw *fallbackWriter) WriteHeader(status int) {
+	if fw.started || fw.declined {
+		return
+	}
+	if status == http.StatusNotFound {
+		fw.declined = true
+		return
+	}
+	fw.started = true
+	fw.ResponseWriter.WriteHeader(status)
+}
+
+func (fw *fallbackWriter) Write(b []byte) (int, error) {
+	if !fw.started && !fw.declined {
+		fw.WriteHeader(http.StatusOK)
+	}
+	if fw.declined {
+		// discard the body of the declined response
+		return len(b), nil
+	}
+	return fw.ResponseWriter.Write(b)
+}
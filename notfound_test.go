@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestNotFoundFallbacks(t *testing.T) {
+	router := &customRouter{}
+	router.addTemplateRoutes([]string{"/api/v3/%s/%s"})
+
+	// first fallback serves static files and declines with a 404 for anything else
+	static := fstest.MapFS{"robots.txt": &fstest.MapFile{Data: []byte("User-agent: *\n")}}
+	router.AddNotFoundFallback(http.FileServerFS(static))
+
+	// second fallback declines by writing nothing for paths it doesn't own
+	router.AddNotFoundFallback(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/legacy/") {
+			return
+		}
+		w.WriteHeader(http.StatusGone)
+		fmt.Fprintln(w, "legacy endpoint removed")
+	}))
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "route still wins over fallbacks",
+			path:           "/api/v3/id1/id2",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Path parameters received:\nParameter 1: id1\nParameter 2: id2\n",
+		},
+		{
+			name:           "first fallback handles",
+			path:           "/robots.txt",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "User-agent: *\n",
+		},
+		{
+			name:           "first fallback declines and the second handles",
+			path:           "/legacy/report",
+			expectedStatus: http.StatusGone,
+			expectedBody:   "legacy endpoint removed\n",
+		},
+		{
+			name:           "every fallback declines",
+			path:           "/missing",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OpenAPIPaths returns an OpenAPI style "paths" object for the route table, i.e for documentation tooling.
+// Each template becomes a path with its placeholders as "{name}", or "{pN}" for the Nth positional param,
+// so "/foo/bar/%s/baz/%s/qux" becomes "/foo/bar/{p1}/baz/{p2}/qux". Under each path every method maps
+// to its path parameter definitions, typed integer for '%d' params and string otherwise.
+// OpenAPI can't express optional segments, so an optional version prefix is left out and doesn't count
+// towards N. Routes serving every method like redirects are left out, as are routes registered with
+// HandleRegex, which have no template to translate.
+func (r *customRouter) OpenAPIPaths() map[string]any {
+	paths := make(map[string]any)
+	for _, rt := range r.routeSnapshot() {
+		// regex routes have no structural pattern, their template is the regex itself
+		if rt.method == anyMethod || rt.structural == nil {
+			continue
+		}
+
+		path, params := openAPIPath(rt)
+		operations, ok := paths[path].(map[string]any)
+		if !ok {
+			operations = make(map[string]any)
+			paths[path] = operations
+		}
+
+		parameters := make([]map[string]any, 0, len(params))
+		for _, param := range params {
+			parameters = append(parameters, map[string]any{
+				"name":     openAPIParamName(param),
+				"in":       "path",
+				"required": true,
+				"schema":   openAPISchema(param),
+			})
+		}
+		operations[strings.ToLower(rt.effectiveMethod())] = map[string]any{"parameters": parameters}
+	}
+	return paths
+}
+
+// returns the OpenAPI path for the route's template along with the params appearing in it
+func openAPIPath(rt *route) (string, []ParamInfo) {
+	pathTemplate, _, _ := splitQueryTemplate(rt.template)
+
+	var sb strings.Builder
+	var params []ParamInfo
+	walked, optional := 0, 0
+	add := func(param ParamInfo) {
+		// numbered among the params that appear in the path
+		param.Index -= optional
+		sb.WriteString("{" + openAPIParamName(param) + "}")
+		params = append(params, param)
+	}
+	walkTemplate(pathTemplate, templateOptions{}, func(static string) {
+		sb.WriteString(static)
+	}, func(param ParamInfo, _ string) {
+		walked++
+		if param.Optional {
+			optional++
+			return
+		}
+		add(param)
+	})
+
+	// params the template doesn't show, i.e the remainder of a prefix route
+	for _, param := range rt.params[min(walked, len(rt.params)):] {
+		add(param)
+	}
+	return sb.String(), params
+}
+
+func openAPIParamName(param ParamInfo) string {
+	if param.Name != "" {
+		return param.Name
+	}
+	return fmt.Sprintf("p%d", param.Index)
+}
+
+// returns the schema for a param inferred from its class
+func openAPISchema(param ParamInfo) map[string]any {
+	switch param.Class {
+	case "[0-9]+":
+		return map[string]any{"type": "integer"}
+	case dateParamClass:
+		return map[string]any{"type": "string", "format": "date"}
+	case defaultParamClass, remainderClass:
+		return map[string]any{"type": "string"}
+	default:
+		return map[string]any{"type": "string", "pattern": "^" + param.Class + "$"}
+	}
+}
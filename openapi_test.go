@@ -0,0 +1,99 @@
+package main
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestOpenAPIPaths(t *testing.T) {
+	router := &customRouter{}
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	router.HandleFunc("/foo/bar/%s/baz/%s/qux", noop)
+	router.HandleFunc("/users/{userID}/orders/%d", noop)
+	router.HandleMethodFunc(http.MethodDelete, "/users/{userID}/orders/%d", noop)
+	router.HandleFunc("/health", noop)
+
+	stringSchema := map[string]any{"type": "string"}
+	integerSchema := map[string]any{"type": "integer"}
+	pathParam := func(name string, schema map[string]any) map[string]any {
+		return map[string]any{"name": name, "in": "path", "required": true, "schema": schema}
+	}
+	userOrderParams := map[string]any{"parameters": []map[string]any{
+		pathParam("userID", stringSchema),
+		pathParam("p2", integerSchema),
+	}}
+
+	expected := map[string]any{
+		"/foo/bar/{p1}/baz/{p2}/qux": map[string]any{
+			"get": map[string]any{"parameters": []map[string]any{
+				pathParam("p1", stringSchema),
+				pathParam("p2", stringSchema),
+			}},
+		},
+		"/users/{userID}/orders/{p2}": map[string]any{
+			"get":    userOrderParams,
+			"delete": userOrderParams,
+		},
+		"/health": map[string]any{
+			"get": map[string]any{"parameters": []map[string]any{}},
+		},
+	}
+
+	result := router.OpenAPIPaths()
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("OpenAPIPaths() = %v\nwant %v", result, expected)
+	}
+}
+
+func TestOpenAPIPathsSpecialParams(t *testing.T) {
+	router := &customRouter{}
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	router.HandleFunc("(/v%d)?/reports/%t", noop)
+	router.HandleFunc("(/v%d)?/orders/%s/items/%d", noop)
+	router.HandlePrefix("/static/", noop)
+	router.HandleRegex(regexp.MustCompile(`^/legacy/(\d+)$`), noop)
+
+	tests := []struct {
+		path     string
+		expected []map[string]any
+	}{
+		{
+			path: "/reports/{p1}",
+			expected: []map[string]any{
+				{"name": "p1", "in": "path", "required": true, "schema": map[string]any{"type": "string", "format": "date"}},
+			},
+		},
+		{
+			path: "/orders/{p1}/items/{p2}",
+			expected: []map[string]any{
+				{"name": "p1", "in": "path", "required": true, "schema": map[string]any{"type": "string"}},
+				{"name": "p2", "in": "path", "required": true, "schema": map[string]any{"type": "integer"}},
+			},
+		},
+		{
+			path: "/static/{p1}",
+			expected: []map[string]any{
+				{"name": "p1", "in": "path", "required": true, "schema": map[string]any{"type": "string"}},
+			},
+		},
+	}
+
+	paths := router.OpenAPIPaths()
+	if len(paths) != len(tests) {
+		t.Errorf("expected only template routes, got %v", paths)
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			operations, ok := paths[tt.path].(map[string]any)
+			if !ok {
+				t.Fatalf("expected path %q, got %v", tt.path, paths)
+			}
+			get := operations["get"].(map[string]any)
+			if !reflect.DeepEqual(get["parameters"], tt.expected) {
+				t.Errorf("parameters = %v; want %v", get["parameters"], tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+// result of matching a request path against the route table, reported to OnMatch/OnMiss
+type MatchOutcome int
+
+const (
+	// a route matched the path, including each param's class
+	Matched MatchOutcome = iota
+	// a route matched the shape of the path but at least one param failed its class
+	StructuralOnly
+	// no route matched the path
+	NoMatch
+)
+
+func (o MatchOutcome) String() string {
+	switch o {
+	case Matched:
+		return "Matched"
+	case StructuralOnly:
+		return "StructuralOnly"
+	case NoMatch:
+		return "NoMatch"
+	default:
+		return "MatchOutcome(unknown)"
+	}
+}
+
+// classifies a path that no route matched strictly
+func (r *customRouter) missOutcome(path string) MatchOutcome {
+	for _, route := range r.routeSnapshot() {
+		if route.structural == nil {
+			continue
+		}
+		if route.structural.MatchString(path) {
+			return StructuralOnly
+		}
+	}
+	return NoMatch
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMatchOutcomeCallbacks(t *testing.T) {
+	var outcomes []MatchOutcome
+	record := func(req *http.Request, outcome MatchOutcome) {
+		outcomes = append(outcomes, outcome)
+	}
+
+	router := &customRouter{OnMatch: record, OnMiss: record}
+	router.addTemplateRoutes([]string{"/foo/bar/%s/baz/%s/qux"})
+
+	tests := []struct {
+		name            string
+		path            string
+		expectedStatus  int
+		expectedOutcome MatchOutcome
+	}{
+		{
+			name:            "matched",
+			path:            "/foo/bar/alpha123/baz/beta456/qux",
+			expectedStatus:  http.StatusOK,
+			expectedOutcome: Matched,
+		},
+		{
+			name:            "structural only - non-alphanumeric param",
+			path:            "/foo/bar/alpha-123/baz/beta456/qux",
+			expectedStatus:  http.StatusNotFound,
+			expectedOutcome: StructuralOnly,
+		},
+		{
+			name:            "no match - different shape",
+			path:            "/foo/bar/alpha123/qux",
+			expectedStatus:  http.StatusNotFound,
+			expectedOutcome: NoMatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcomes = nil
+
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			if len(outcomes) != 1 || outcomes[0] != tt.expectedOutcome {
+				t.Errorf("unexpected outcomes reported: got %v want [%v]", outcomes, tt.expectedOutcome)
+			}
+		})
+	}
+}
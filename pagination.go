@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// sets an RFC 5988 'Link' header with "prev" and "next" URLs for a paginated list, built from the request's
+// path and query with the 'page' and 'per_page' query params replaced. Pages are 1-indexed.
+func setPaginationLinks(w http.ResponseWriter, r *http.Request, page, perPage, total int) {
+	if perPage <= 0 {
+		return
+	}
+	lastPage := (total + perPage - 1) / perPage
+
+	var links []string
+	// pages past the last have nothing to go back to, they're not part of the list
+	if page > 1 && page <= lastPage {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL(r.URL, page-1, perPage)))
+	}
+	if page < lastPage {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(r.URL, page+1, perPage)))
+	}
+
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+}
+
+// returns the request URL's path and query with the pagination params set for the given page
+func pageURL(u *url.URL, page, perPage int) string {
+	query := u.Query()
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(perPage))
+	return (&url.URL{Path: u.Path, RawQuery: query.Encode()}).String()
+}
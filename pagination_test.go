@@ -0,0 +1,64 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSetPaginationLinks(t *testing.T) {
+	tests := []struct {
+		name         string
+		page         int
+		total        int
+		expectedLink string
+	}{
+		{
+			name:         "first page",
+			page:         1,
+			total:        25,
+			expectedLink: `</users/u1/posts?page=2&per_page=10&sort=asc>; rel="next"`,
+		},
+		{
+			name:  "middle page",
+			page:  2,
+			total: 25,
+			expectedLink: `</users/u1/posts?page=1&per_page=10&sort=asc>; rel="prev", ` +
+				`</users/u1/posts?page=3&per_page=10&sort=asc>; rel="next"`,
+		},
+		{
+			name:         "last page",
+			page:         3,
+			total:        25,
+			expectedLink: `</users/u1/posts?page=2&per_page=10&sort=asc>; rel="prev"`,
+		},
+		{
+			name:         "just past the last page",
+			page:         4,
+			total:        25,
+			expectedLink: "",
+		},
+		{
+			name:         "single page",
+			page:         1,
+			total:        5,
+			expectedLink: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/users/u1/posts?sort=asc&page=9", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			setPaginationLinks(rr, req, tt.page, 10, tt.total)
+
+			if got := rr.Header().Get("Link"); got != tt.expectedLink {
+				t.Errorf("unexpected Link header:\n got %q\nwant %q", got, tt.expectedLink)
+			}
+		})
+	}
+}
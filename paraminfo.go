@@ -0,0 +1,14 @@
+package main
+
+import "slices"
+
+// RouteParamInfo returns the path params declared by the first route registered with template, with the
+// regex class each must match, i.e to generate API docs from the route table. Returns nil if no route
+// uses the template or it declares no params.
+func (r *customRouter) RouteParamInfo(template string) []ParamInfo {
+	routes := r.routesForTemplate(template)
+	if len(routes) == 0 {
+		return nil
+	}
+	return slices.Clone(routes[0].params)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestRouteParamInfo(t *testing.T) {
+	router := &customRouter{}
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	router.HandleFunc("/users/%s", noop)
+	router.HandleFunc("/orders/%d/items/{item}", noop)
+	router.HandleFunc("/posts/{slug:[a-z-]+}", noop)
+	router.HandleFunc("/health", noop)
+
+	tests := []struct {
+		name     string
+		template string
+		expected []ParamInfo
+	}{
+		{
+			name:     "default class",
+			template: "/users/%s",
+			expected: []ParamInfo{{Index: 1, Class: "[a-zA-Z0-9]+", Verb: 's'}},
+		},
+		{
+			name:     "numeric class",
+			template: "/orders/%d/items/{item}",
+			expected: []ParamInfo{
+				{Index: 1, Class: "[0-9]+", Verb: 'd'},
+				{Index: 2, Name: "item", Class: "[a-zA-Z0-9]+"},
+			},
+		},
+		{
+			name:     "custom class",
+			template: "/posts/{slug:[a-z-]+}",
+			expected: []ParamInfo{{Index: 1, Name: "slug", Class: "[a-z-]+"}},
+		},
+		{
+			name:     "no params",
+			template: "/health",
+		},
+		{
+			name:     "unregistered template",
+			template: "/missing/%s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := router.RouteParamInfo(tt.template)
+			if !slices.Equal(result, tt.expected) {
+				t.Errorf("RouteParamInfo(%q) = %+v; want %+v", tt.template, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// context key for the names of the captured params, see paramKey for the values
+type paramNamesKey struct{}
+
+// context key for the matched route's param aliases, see customRouter.AliasParam
+type paramAliasesKey struct{}
+
+// a captured path parameter, Name is empty for positional ('%s') params
+type orderedParam struct {
+	Name  string
+	Value string
+}
+
+// returns the 1-indexed path parameter stored by customRouter.ServeHTTP, or "" if it wasn't captured
+func getParam(r *http.Request, index int) string {
+	value, _ := r.Context().Value(paramKey(index)).(string)
+	return value
+}
+
+// returns the 1-indexed path parameter and whether it was captured, telling an empty param apart from
+// one that is absent, i.e an optional regex group that didn't participate in the match
+func getParamOK(r *http.Request, index int) (string, bool) {
+	value, ok := r.Context().Value(paramKey(index)).(string)
+	return value, ok
+}
+
+// returns the 1-indexed path parameter, or def when it wasn't captured or is empty
+func getParamOr(r *http.Request, index int, def string) string {
+	if value := getParam(r, index); value != "" {
+		return value
+	}
+	return def
+}
+
+// returns the 1-indexed path parameter lowercased with strings.ToLower, i.e for case-insensitive lookups;
+// the stored param, and so matching, is unchanged
+func getParamLower(r *http.Request, index int) string {
+	return strings.ToLower(getParam(r, index))
+}
+
+// returns the 1-indexed path parameter case-folded for comparisons: each rune is mapped to the lowercase
+// of its uppercase, so 'ſ' and 'S' fold to 's' and both 'İ' and 'ı' to 'i'. Folding is rune by rune, so
+// expansions like 'ß' to "ss" don't happen.
+func getParamFold(r *http.Request, index int) string {
+	return strings.Map(func(c rune) rune {
+		return unicode.ToLower(unicode.ToUpper(c))
+	}, getParam(r, index))
+}
+
+// returns the value captured by a '{name}' placeholder, or "" if there is no such param.
+// Names registered with customRouter.AliasParam resolve to the param they alias, and "method" to the
+// request's method when no param has that name.
+func getParamByName(r *http.Request, name string) string {
+	value, _ := getParamByNameOK(r, name)
+	return value
+}
+
+// like getParamByName but reports whether the param was captured, so an empty value, i.e from an
+// optional query param sent as "?q=", can be told apart from an absent one
+func getParamByNameOK(r *http.Request, name string) (string, bool) {
+	names, _ := r.Context().Value(paramNamesKey{}).([]string)
+	if i := slices.Index(names, name); i != -1 {
+		return getParamOK(r, i+1)
+	}
+
+	aliases, _ := r.Context().Value(paramAliasesKey{}).(map[string]string)
+	if current, ok := aliases[name]; ok {
+		if i := slices.Index(names, current); i != -1 {
+			return getParamOK(r, i+1)
+		}
+	}
+	if name == methodParamName {
+		method, ok := r.Context().Value(methodKey{}).(string)
+		return method, ok
+	}
+	return "", false
+}
+
+// returns every captured param in capture order, with names where the template provided them
+func getAllParamsOrdered(r *http.Request) []orderedParam {
+	names, _ := r.Context().Value(paramNamesKey{}).([]string)
+	params := make([]orderedParam, 0, len(names))
+	for i, name := range names {
+		params = append(params, orderedParam{Name: name, Value: getParam(r, i+1)})
+	}
+	return params
+}
+
+// AliasParam lets getParamByName(r, oldName) keep resolving after a '{name}' placeholder in template is
+// renamed to newName, so handlers can be migrated to the new name gradually
+func (r *customRouter) AliasParam(template, oldName, newName string) error {
+	// positional params have an empty name, so aliasing to or from "" would match one of those
+	if oldName == "" || newName == "" {
+		return fmt.Errorf("cannot alias param '%s' to '%s': names must not be empty", oldName, newName)
+	}
+
+	routes := r.routesForTemplate(template)
+	if len(routes) == 0 {
+		return fmt.Errorf("no route registered for template '%s'", template)
+	}
+
+	for _, rt := range routes {
+		if !slices.Contains(rt.pattern.SubexpNames(), newName) {
+			return fmt.Errorf("template '%s' has no param named '%s'", template, newName)
+		}
+	}
+
+	for _, rt := range routes {
+		if rt.aliases == nil {
+			rt.aliases = make(map[string]string)
+		}
+		rt.aliases[oldName] = newName
+	}
+	return nil
+}
+
+// GetParam returns the 1-indexed path parameter converted to T, which must be string, int, int64 or bool.
+// It errors if the param wasn't captured, can't be converted or T is unsupported.
+func GetParam[T any](r *http.Request, index int) (T, error) {
+	var zero T
+	value, ok := r.Context().Value(paramKey(index)).(string)
+	if !ok {
+		return zero, fmt.Errorf("parameter %d: not captured", index)
+	}
+
+	var converted any
+	var err error
+	switch any(zero).(type) {
+	case string:
+		converted = value
+	case int:
+		converted, err = strconv.Atoi(value)
+	case int64:
+		converted, err = strconv.ParseInt(value, 10, 64)
+	case bool:
+		converted, err = strconv.ParseBool(value)
+	default:
+		return zero, fmt.Errorf("parameter %d: unsupported type %T", index, zero)
+	}
+	if err != nil {
+		return zero, fmt.Errorf("parameter %d: %w", index, err)
+	}
+	return converted.(T), nil
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestGetAllParamsOrdered(t *testing.T) {
+	router := &customRouter{}
+
+	var got []orderedParam
+	var userID, postID string
+	router.HandleFunc("/users/{userID}/posts/%s/{commentID}", func(w http.ResponseWriter, r *http.Request) {
+		got = getAllParamsOrdered(r)
+		userID = getParamByName(r, "userID")
+		postID = getParam(r, 2)
+	})
+
+	req, err := http.NewRequest("GET", "/users/u42/posts/p7/c9", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	expected := []orderedParam{
+		{Name: "userID", Value: "u42"},
+		{Name: "", Value: "p7"},
+		{Name: "commentID", Value: "c9"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("getAllParamsOrdered() = %+v; want %+v", got, expected)
+	}
+
+	if userID != "u42" {
+		t.Errorf("getParamByName(userID) = %q; want %q", userID, "u42")
+	}
+
+	if postID != "p7" {
+		t.Errorf("getParam(2) = %q; want %q", postID, "p7")
+	}
+}
+
+func TestGetAllParamsOrderedWithoutRouter(t *testing.T) {
+	req, err := http.NewRequest("GET", "/users/u42", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if params := getAllParamsOrdered(req); len(params) != 0 {
+		t.Errorf("expected no params for a request not served by the router, got %+v", params)
+	}
+}
+
+func TestAliasParam(t *testing.T) {
+	router := &customRouter{}
+	routeTemplateStr := "/accounts/{accountID}/orders/%s"
+
+	var oldName, newName string
+	router.HandleFunc(routeTemplateStr, func(w http.ResponseWriter, r *http.Request) {
+		oldName = getParamByName(r, "userID")
+		newName = getParamByName(r, "accountID")
+	})
+
+	if err := router.AliasParam(routeTemplateStr, "userID", "accountID"); err != nil {
+		t.Fatalf("unexpected error aliasing param: %v", err)
+	}
+
+	req, err := http.NewRequest("GET", "/accounts/acc42/orders/o1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	if oldName != "acc42" || newName != "acc42" {
+		t.Errorf("expected both names to resolve to %q, got old=%q new=%q", "acc42", oldName, newName)
+	}
+
+	t.Run("unknown template", func(t *testing.T) {
+		if err := router.AliasParam("/not/registered", "userID", "accountID"); err == nil {
+			t.Error("expected an error aliasing a param on an unregistered template")
+		}
+	})
+
+	t.Run("unknown new name", func(t *testing.T) {
+		if err := router.AliasParam(routeTemplateStr, "userID", "customerID"); err == nil {
+			t.Error("expected an error aliasing to a param the template doesn't have")
+		}
+	})
+
+	t.Run("empty name", func(t *testing.T) {
+		// the template's positional '%s' param has an empty name
+		if err := router.AliasParam(routeTemplateStr, "userID", ""); err == nil {
+			t.Error("expected an error aliasing to an empty name")
+		}
+		if err := router.AliasParam(routeTemplateStr, "", "accountID"); err == nil {
+			t.Error("expected an error aliasing from an empty name")
+		}
+	})
+}
+
+func TestGetParamGeneric(t *testing.T) {
+	router := &customRouter{}
+
+	var (
+		id      int
+		big     int64
+		enabled bool
+		name    string
+		errs    []error
+	)
+	router.HandleFunc("/flags/%s/%s/%s/%s", func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		id, err = GetParam[int](r, 1)
+		errs = append(errs, err)
+		big, err = GetParam[int64](r, 2)
+		errs = append(errs, err)
+		enabled, err = GetParam[bool](r, 3)
+		errs = append(errs, err)
+		name, err = GetParam[string](r, 4)
+		errs = append(errs, err)
+	})
+
+	req, err := http.NewRequest("GET", "/flags/42/9000000000/true/beta", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("unexpected error converting param %d: %v", i+1, err)
+		}
+	}
+	if id != 42 || big != 9000000000 || !enabled || name != "beta" {
+		t.Errorf("unexpected converted params: %d %d %v %q", id, big, enabled, name)
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		var conversionErr, unsupportedErr, missingErr error
+		router := &customRouter{}
+		router.HandleFunc("/items/%s", func(w http.ResponseWriter, r *http.Request) {
+			_, conversionErr = GetParam[int](r, 1)
+			_, unsupportedErr = GetParam[float64](r, 1)
+			_, missingErr = GetParam[string](r, 2)
+		})
+
+		req, err := http.NewRequest("GET", "/items/abc", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		if conversionErr == nil {
+			t.Error("expected an error converting a non-numeric param to int")
+		}
+		if unsupportedErr == nil || unsupportedErr.Error() != "parameter 1: unsupported type float64" {
+			t.Errorf("unexpected error for an unsupported type: %v", unsupportedErr)
+		}
+		if missingErr == nil || missingErr.Error() != "parameter 2: not captured" {
+			t.Errorf("unexpected error for a missing param: %v", missingErr)
+		}
+	})
+}
+
+func TestGetParamOK(t *testing.T) {
+	router := &customRouter{}
+	// the first group may match an empty string, the second is optional
+	router.HandleRegexN(regexp.MustCompile(`^/items/([a-z]*)(?:/(draft))?$`), func(w http.ResponseWriter, r *http.Request) {
+		for i := 1; i <= 3; i++ {
+			value, ok := getParamOK(r, i)
+			fmt.Fprintf(w, "%d:%q:%v ", i, value, ok)
+		}
+	}, 1)
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedBody string
+	}{
+		{
+			name:         "empty param is present",
+			path:         "/items/",
+			expectedBody: `1:"":true 2:"":false 3:"":false `,
+		},
+		{
+			name:         "optional param present",
+			path:         "/items/abc/draft",
+			expectedBody: `1:"abc":true 2:"draft":true 3:"":false `,
+		},
+		{
+			name:         "optional param absent",
+			path:         "/items/abc",
+			expectedBody: `1:"abc":true 2:"":false 3:"":false `,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestGetParamOr(t *testing.T) {
+	router := &customRouter{}
+	router.HandleRegexN(regexp.MustCompile(`^/items/([a-z]*)(?:/(draft|final))?$`), func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s %s", getParamOr(r, 1, "all"), getParamOr(r, 2, "final"), getParamOr(r, 3, "none"))
+	}, 1)
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedBody string
+	}{
+		{name: "present params", path: "/items/abc/draft", expectedBody: "abc draft none"},
+		{name: "absent optional param", path: "/items/abc", expectedBody: "abc final none"},
+		{name: "empty param", path: "/items/", expectedBody: "all final none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestGetParamLowerAndFold(t *testing.T) {
+	router := &customRouter{}
+	router.HandleFunc("/users/{name:[^/]+}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s|%s|%s", getParam(r, 1), getParamLower(r, 1), getParamFold(r, 1))
+	})
+
+	tests := []struct {
+		name          string
+		value         string
+		expectedLower string
+		expectedFold  string
+	}{
+		{name: "ASCII", value: "JohnDoe", expectedLower: "johndoe", expectedFold: "johndoe"},
+		{name: "dotted capital I", value: "İstanbul", expectedLower: "istanbul", expectedFold: "istanbul"},
+		{name: "dotless small i", value: "ıIi", expectedLower: "ıii", expectedFold: "iii"},
+		{name: "long s", value: "ſtraße", expectedLower: "ſtraße", expectedFold: "straße"},
+		{name: "Kelvin sign", value: "K", expectedLower: "k", expectedFold: "k"},
+		{name: "Greek final sigma", value: "ΟΔΟΣ ς", expectedLower: "οδοσ ς", expectedFold: "οδοσ σ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/users/"+url.PathEscape(tt.value), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			// the stored param keeps its case
+			expected := tt.value + "|" + tt.expectedLower + "|" + tt.expectedFold
+			if rr.Body.String() != expected {
+				t.Errorf("got %q want %q", rr.Body.String(), expected)
+			}
+		})
+	}
+}
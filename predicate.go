@@ -0,0 +1,17 @@
+package main
+
+import "net/http"
+
+// HandleFuncIf registers a route that's only considered while enabled reports true, i.e behind a
+// feature flag. enabled is called for every request the route could serve so the flag can flip live;
+// while it's off requests fall through to later routes or a 404.
+func (r *customRouter) HandleFuncIf(pattern string, handler http.HandlerFunc, enabled func() bool) {
+	r.handle(pattern, handler, func(rt *route) {
+		rt.enabled = enabled
+	})
+}
+
+// reports whether the route should be considered for the current request
+func (rt *route) active() bool {
+	return rt.enabled == nil || rt.enabled()
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestHandleFuncIf(t *testing.T) {
+	var flag atomic.Bool
+	router := &customRouter{}
+	router.HandleFuncIf("/checkout/%s", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "new checkout", getParam(r, 1))
+	}, flag.Load)
+	router.HandleFuncIf("/beta/%s", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "beta", getParam(r, 1))
+	}, flag.Load)
+	router.HandleFunc("/checkout/%s", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "old checkout", getParam(r, 1))
+	})
+
+	tests := []struct {
+		name           string
+		enabled        bool
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "disabled falls through to the next route",
+			path:           "/checkout/c1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "old checkout c1\n",
+		},
+		{
+			name:           "disabled without another route",
+			path:           "/beta/b1",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+		{
+			name:           "enabled",
+			enabled:        true,
+			path:           "/checkout/c1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "new checkout c1\n",
+		},
+		{
+			name:           "enabled without another route",
+			enabled:        true,
+			path:           "/beta/b1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "beta b1\n",
+		},
+		{
+			name:           "disabled again",
+			path:           "/checkout/c1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "old checkout c1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flag.Store(tt.enabled)
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
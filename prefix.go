@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// context key for the part of the path after a prefix route's prefix
+type remainderKey struct{}
+
+// class of the param capturing the rest of the path after a prefix, including newlines from an
+// encoded "%0A"
+const remainderClass = "(?s:.*)"
+
+// HandlePrefix registers a route matching every path starting with prefix, which may contain
+// placeholders like any template, i.e "/static/" or "/users/%s/files/". The rest of the path is
+// captured as the last param and is available via remainderPath; it may be empty.
+func (r *customRouter) HandlePrefix(prefix string, handler http.HandlerFunc) {
+	r.handle(prefix, handler, func(rt *route) {
+		// IgnoreTrailingSlash trims the prefix's slash, which must still end a segment so "/static/"
+		// doesn't match "/staticevil/x"
+		boundary := ""
+		if strings.HasSuffix(prefix, "/") && !strings.HasSuffix(rt.template, "/") {
+			boundary = "(?:/|$)"
+		}
+		rt.pattern = withRemainder(rt.pattern, boundary)
+		rt.structural = withRemainder(rt.structural, boundary)
+		rt.params = append(rt.params, ParamInfo{Index: len(rt.params) + 1, Class: remainderClass})
+		rt.remainder = true
+	})
+}
+
+// turns an anchored template pattern into one also capturing whatever follows it after boundary, a
+// regex the remainder must be preceded by
+func withRemainder(pattern *regexp.Regexp, boundary string) *regexp.Regexp {
+	return regexp.MustCompile(strings.TrimSuffix(pattern.String(), "$") + boundary + "(" + remainderClass + ")$")
+}
+
+// returns the part of the path after the prefix of the prefix route that matched, i.e "css/app.css"
+// for "/static/css/app.css" with the prefix "/static/"; "" for other routes or an empty remainder
+func remainderPath(r *http.Request) string {
+	remainder, _ := r.Context().Value(remainderKey{}).(string)
+	return remainder
+}
+
+// returns the final non-empty segment of path, i.e "c" for both "/a/b/c" and "/a/b/c/", or "" if it
+// has none; handy for the basename of a remainderPath
+func lastSegment(path string) string {
+	path = strings.TrimRight(path, "/")
+	return path[strings.LastIndexByte(path, '/')+1:]
+}
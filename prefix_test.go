@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandlePrefix(t *testing.T) {
+	router := &customRouter{}
+	router.HandlePrefix("/static/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "static %q\n", remainderPath(r))
+	})
+	router.HandlePrefix("/users/%s/files/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "user %s file %q (param 2 %q)\n", getParam(r, 1), remainderPath(r), getParam(r, 2))
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "single segment remainder",
+			path:           "/static/app.css",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "static \"app.css\"\n",
+		},
+		{
+			name:           "nested remainder",
+			path:           "/static/css/vendor/app.css",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "static \"css/vendor/app.css\"\n",
+		},
+		{
+			name:           "empty remainder",
+			path:           "/static/",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "static \"\"\n",
+		},
+		{
+			name:           "remainder with an encoded newline",
+			path:           "/static/a%0Ab.txt",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "static \"a\\nb.txt\"\n",
+		},
+		{
+			name:           "remainder after a param",
+			path:           "/users/u1/files/docs/a.txt",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "user u1 file \"docs/a.txt\" (param 2 \"docs/a.txt\")\n",
+		},
+		{
+			name:           "prefix not matched",
+			path:           "/staticfiles",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+
+	t.Run("other routes have no remainder", func(t *testing.T) {
+		router.HandleFunc("/plain/%s", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "%q\n", remainderPath(r))
+		})
+		req, err := http.NewRequest("GET", "/plain/p1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		if rr.Body.String() != "\"\"\n" {
+			t.Errorf("expected an empty remainder, got %q", rr.Body.String())
+		}
+	})
+}
+
+func TestHandlePrefixIgnoreTrailingSlash(t *testing.T) {
+	router := &customRouter{IgnoreTrailingSlash: true}
+	router.HandlePrefix("/static/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, remainderPath(r))
+	})
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{path: "/static/css/app.css", expectedStatus: http.StatusOK, expectedBody: "css/app.css"},
+		{path: "/static/", expectedStatus: http.StatusOK, expectedBody: ""},
+		{path: "/static", expectedStatus: http.StatusOK, expectedBody: ""},
+		{path: "/staticevil/x", expectedStatus: http.StatusNotFound, expectedBody: "404 page not found\n"},
+	}
+
+	for _, tt := range tests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		if rr.Code != tt.expectedStatus || rr.Body.String() != tt.expectedBody {
+			t.Errorf("unexpected response for %s: got %d %q want %d %q",
+				tt.path, rr.Code, rr.Body.String(), tt.expectedStatus, tt.expectedBody)
+		}
+	}
+}
+
+func TestLastSegment(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "/a/b/c", expected: "c"},
+		{path: "/a/b/c/", expected: "c"},
+		{path: "/a/b/c//", expected: "c"},
+		{path: "css/app.css", expected: "app.css"},
+		{path: "file", expected: "file"},
+		{path: "/", expected: ""},
+		{path: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		if result := lastSegment(tt.path); result != tt.expected {
+			t.Errorf("lastSegment(%q) = %q; want %q", tt.path, result, tt.expected)
+		}
+	}
+}
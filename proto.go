@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// HandleFuncMinProto registers a route only serving requests made over HTTP major.minor or later,
+// i.e 1, 1 to turn away HTTP/1.0 clients or 2, 0 to require HTTP/2; older protocols get a 505
+func (r *customRouter) HandleFuncMinProto(pattern string, major, minor int, handler http.HandlerFunc) {
+	if handler == nil {
+		panic(nilHandlerError(pattern))
+	}
+	r.handle(pattern, func(w http.ResponseWriter, req *http.Request) {
+		if !req.ProtoAtLeast(major, minor) {
+			r.logger().Infof("Rejected %s %s over %s, HTTP/%d.%d required", req.Method, req.URL.Path, req.Proto, major, minor)
+			r.writeError(w, http.StatusHTTPVersionNotSupported,
+				fmt.Sprintf("HTTP version not supported: HTTP/%d.%d or later required", major, minor))
+			return
+		}
+		handler(w, req)
+	})
+}
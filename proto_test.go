@@ -0,0 +1,43 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleFuncMinProto(t *testing.T) {
+	router := &customRouter{}
+	router.HandleFuncMinProto("/v11/%s", 1, 1, func(w http.ResponseWriter, r *http.Request) {})
+	router.HandleFuncMinProto("/v2/%s", 2, 0, func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		name           string
+		path           string
+		major, minor   int
+		expectedStatus int
+	}{
+		{name: "HTTP/1.0 below HTTP/1.1", path: "/v11/x", major: 1, minor: 0, expectedStatus: http.StatusHTTPVersionNotSupported},
+		{name: "HTTP/1.1 meets HTTP/1.1", path: "/v11/x", major: 1, minor: 1, expectedStatus: http.StatusOK},
+		{name: "HTTP/2 exceeds HTTP/1.1", path: "/v11/x", major: 2, minor: 0, expectedStatus: http.StatusOK},
+		{name: "HTTP/1.1 below HTTP/2", path: "/v2/x", major: 1, minor: 1, expectedStatus: http.StatusHTTPVersionNotSupported},
+		{name: "HTTP/2 meets HTTP/2", path: "/v2/x", major: 2, minor: 0, expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.ProtoMajor, req.ProtoMinor = tt.major, tt.minor
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+		})
+	}
+}
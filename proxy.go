@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+)
+
+// name of the param selecting the upstream of a newServiceProxyHandler
+const serviceParamName = "name"
+
+// creates an http.HandlerFunc reverse proxying to the upstream registry maps the "name" param to, for a
+// prefix route like HandlePrefix("/svc/{name}/", ...). The rest of the path after the prefix and the
+// query are forwarded, so "/svc/users/v1/list?page=2" with "users" at "http://users.internal/api" goes to
+// "http://users.internal/api/v1/list?page=2". Unknown names get a 404. It panics if an upstream isn't an
+// absolute URL, like other invalid registrations.
+func newServiceProxyHandler(registry map[string]string) http.HandlerFunc {
+	proxies := make(map[string]*httputil.ReverseProxy, len(registry))
+	for name, upstream := range registry {
+		target, err := url.Parse(upstream)
+		if err != nil || !target.IsAbs() || target.Host == "" {
+			panic(fmt.Errorf("invalid upstream '%s' for service '%s'", upstream, name))
+		}
+		proxies[name] = &httputil.ReverseProxy{
+			Rewrite: func(pr *httputil.ProxyRequest) {
+				// only the remainder is forwarded, appended to the upstream's own path
+				pr.Out.URL.Path = "/" + remainderPath(pr.In)
+				pr.Out.URL.RawPath = ""
+				pr.SetURL(target)
+				pr.SetXForwarded()
+			},
+		}
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		proxy, ok := proxies[getParamByName(r, serviceParamName)]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		proxy.ServeHTTP(w, r)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewServiceProxyHandler(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s?%s", r.Method, r.URL.Path, r.URL.RawQuery)
+	}))
+	defer upstream.Close()
+
+	router := &customRouter{}
+	router.HandlePrefix("/svc/{name}/", newServiceProxyHandler(map[string]string{
+		"users":  upstream.URL + "/api",
+		"orders": upstream.URL,
+	}))
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "known service with upstream path",
+			path:           "/svc/users/v1/list?page=2&sort=name",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "GET /api/v1/list?page=2&sort=name",
+		},
+		{
+			name:           "known service at the upstream root",
+			path:           "/svc/orders/o42",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "GET /o42?",
+		},
+		{
+			name:           "empty remainder",
+			path:           "/svc/orders/",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "GET /?",
+		},
+		{
+			name:           "unknown service",
+			path:           "/svc/billing/invoices",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			body, _ := io.ReadAll(rr.Body)
+			if string(body) != tt.expectedBody {
+				t.Errorf("upstream saw %q; want %q", body, tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestNewServiceProxyHandlerInvalidUpstream(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a relative upstream URL")
+		}
+	}()
+	newServiceProxyHandler(map[string]string{"users": "users.internal/api"})
+}
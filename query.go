@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// context key for the query params declared with BindQuery
+type queryParamsKey struct{}
+
+// returns every route registered with the given template, in registration order; fragment references
+// are expanded as they were when registering, so "{@users}/profile" finds the route it registered
+func (r *customRouter) routesForTemplate(template string) []*route {
+	template, err := r.expandFragments(template)
+	if err != nil {
+		return nil
+	}
+
+	var matched []*route
+	for _, rt := range r.routeSnapshot() {
+		if rt.template == template {
+			matched = append(matched, rt)
+		}
+	}
+	return matched
+}
+
+// BindQuery declares query params for the routes registered with template; when one of them matches,
+// the declared keys are parsed once and made available via getQueryParam alongside the path params
+func (r *customRouter) BindQuery(template string, keys []string) error {
+	routes := r.routesForTemplate(template)
+	if len(routes) == 0 {
+		return fmt.Errorf("no route registered for template '%s'", template)
+	}
+
+	for _, rt := range routes {
+		rt.queryKeys = append(rt.queryKeys, keys...)
+	}
+	return nil
+}
+
+// parses the declared query keys from the request; only the first value of each key is kept
+func parseBoundQuery(req *http.Request, keys []string) map[string]string {
+	query := req.URL.Query()
+	bound := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if values, ok := query[key]; ok && len(values) > 0 {
+			bound[key] = values[0]
+		}
+	}
+	return bound
+}
+
+// returns the value of a query param declared with BindQuery, or "" if it is absent or wasn't declared
+func getQueryParam(r *http.Request, key string) string {
+	bound, _ := r.Context().Value(queryParamsKey{}).(map[string]string)
+	return bound[key]
+}
+
+// a query param required by a route template, i.e "type=%s" from "/search?type=%s"
+type queryMatcher struct {
+	key   string
+	value *regexp.Regexp // anchored pattern for the value, placeholders capture params
+}
+
+// the start of a query template after its '?', a key followed by '='
+var queryKeyPrefixRegex = regexp.MustCompile(`^[a-zA-Z0-9_.-]+=`)
+
+// splits a template into its path and query parts at the first '?' followed by a key and '=', i.e
+// "/search?type=%s". Any other '?' is a regex quantifier, as in "/files/%s\\.jsonp?", and so is the '?' of
+// an optional version prefix or inside a placeholder's class, i.e "{v:colou?r}"
+func splitQueryTemplate(template string) (string, string, bool) {
+	if rest, ok := strings.CutPrefix(template, optionalVersionPrefix); ok {
+		pathTemplate, queryTemplate, hasQuery := splitQueryTemplate(rest)
+		return optionalVersionPrefix + pathTemplate, queryTemplate, hasQuery
+	}
+	for i := 0; i < len(template); i++ {
+		switch template[i] {
+		case '{':
+			if end := closingBrace(template[i:]); end != -1 {
+				i += end
+			}
+		case '?':
+			if queryKeyPrefixRegex.MatchString(template[i+1:]) {
+				return template[:i], template[i+1:], true
+			}
+		}
+	}
+	return template, "", false
+}
+
+// compiles the query part of a template, i.e "type=%s&lang={lang}", into a matcher per key
+func compileQueryTemplate(queryTemplate string) ([]queryMatcher, error) {
+	var matchers []queryMatcher
+	for _, pair := range strings.Split(queryTemplate, "&") {
+		if pair == "" {
+			continue
+		}
+		key, valueTemplate, _ := strings.Cut(pair, "=")
+		value, err := regexp.Compile(makeRegexPatternStr(valueTemplate))
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, queryMatcher{key: key, value: value})
+	}
+	return matchers, nil
+}
+
+// matches the request's query against the route's required query params, returning the captured
+// params and their names; routes without query params in their template match any query
+func (rt *route) matchQuery(req *http.Request) ([]string, []string, bool) {
+	if len(rt.query) == 0 {
+		return nil, nil, true
+	}
+
+	query := req.URL.Query()
+	var params, names []string
+	for _, matcher := range rt.query {
+		if !query.Has(matcher.key) {
+			return nil, nil, false
+		}
+
+		matches := matcher.value.FindStringSubmatch(query.Get(matcher.key))
+		if matches == nil {
+			return nil, nil, false
+		}
+		params = append(params, matches[1:]...)
+		names = append(names, matcher.value.SubexpNames()[1:]...)
+	}
+	return params, names, true
+}
+
+// returns a query param the request repeats, reporting false if none is; when several are the
+// alphabetically first is returned so the error is stable
+func duplicateQueryKey(req *http.Request) (string, bool) {
+	query := req.URL.Query()
+	keys := slices.Sorted(maps.Keys(query))
+	for _, key := range keys {
+		if len(query[key]) > 1 {
+			return key, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBindQuery(t *testing.T) {
+	router := &customRouter{}
+	routeTemplateStr := "/search/%s"
+	router.HandleFunc(routeTemplateStr, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "category=%s page=%q sort=%q undeclared=%q\n",
+			getParam(r, 1), getQueryParam(r, "page"), getQueryParam(r, "sort"), getQueryParam(r, "debug"))
+	})
+
+	if err := router.BindQuery(routeTemplateStr, []string{"page", "sort"}); err != nil {
+		t.Fatalf("unexpected error binding query: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedBody string
+	}{
+		{
+			name:         "declared keys present",
+			path:         "/search/books?page=2&sort=asc&debug=1",
+			expectedBody: "category=books page=\"2\" sort=\"asc\" undeclared=\"\"\n",
+		},
+		{
+			name:         "declared keys absent",
+			path:         "/search/books",
+			expectedBody: "category=books page=\"\" sort=\"\" undeclared=\"\"\n",
+		},
+		{
+			name:         "first value wins for repeated keys",
+			path:         "/search/books?page=3&page=4",
+			expectedBody: "category=books page=\"3\" sort=\"\" undeclared=\"\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != http.StatusOK {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+			}
+
+			if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestBindQueryUnknownTemplate(t *testing.T) {
+	router := &customRouter{}
+	if err := router.BindQuery("/not/registered/%s", []string{"page"}); err == nil {
+		t.Error("expected an error binding query keys to an unregistered template")
+	}
+}
+
+func TestQueryInTemplate(t *testing.T) {
+	router := &customRouter{}
+	router.HandleFunc("/search/%s?type=%s&lang={lang}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "scope=%s type=%s lang=%s\n", getParam(r, 1), getParam(r, 2), getParamByName(r, "lang"))
+	})
+	router.HandleFunc("/search?type=%s", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "type=%s\n", getParam(r, 1))
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "query value captured",
+			path:           "/search?type=image",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "type=image\n",
+		},
+		{
+			name:           "other query params are allowed",
+			path:           "/search?page=2&type=image",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "type=image\n",
+		},
+		{
+			name:           "missing query param",
+			path:           "/search",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+		{
+			name:           "query value fails its class",
+			path:           "/search?type=image-png",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+		{
+			name:           "path and query params captured in order",
+			path:           "/search/docs?lang=en&type=pdf",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "scope=docs type=pdf lang=en\n",
+		},
+		{
+			name:           "one of several query params missing",
+			path:           "/search/docs?type=pdf",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestRejectDuplicateQueryKeys(t *testing.T) {
+	router := &customRouter{RejectDuplicateQueryKeys: true}
+	router.HandleFunc("/search/%s", func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "duplicated key",
+			path:           "/search/books?id=1&sort=asc&id=2",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Bad request: duplicate query parameter 'id'\n",
+		},
+		{
+			name:           "normal query",
+			path:           "/search/books?id=1&sort=asc",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "no query",
+			path:           "/search/books",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestQuestionMarkInPlaceholderClass(t *testing.T) {
+	router := &customRouter{}
+	router.HandleFunc("/colors/{v:colou?r}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, getParamByName(r, "v"))
+	})
+	router.HandleFunc("/shades/{v:gr[ae]y}?tone=%s", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, getParamByName(r, "v"), " ", getParam(r, 2))
+	})
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{path: "/colors/colour", expectedStatus: http.StatusOK, expectedBody: "colour"},
+		{path: "/colors/color", expectedStatus: http.StatusOK, expectedBody: "color"},
+		{path: "/shades/grey?tone=dark", expectedStatus: http.StatusOK, expectedBody: "grey dark"},
+	}
+
+	for _, tt := range tests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		if rr.Code != tt.expectedStatus || rr.Body.String() != tt.expectedBody {
+			t.Errorf("unexpected response for %s: got %d %q want %d %q",
+				tt.path, rr.Code, rr.Body.String(), tt.expectedStatus, tt.expectedBody)
+		}
+	}
+}
+
+func TestQuestionMarkQuantifierInPath(t *testing.T) {
+	router := &customRouter{}
+	router.HandleFunc("/files/%s\\.jsonp?", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, getParam(r, 1))
+	})
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{path: "/files/data.json", expectedStatus: http.StatusOK, expectedBody: "data"},
+		{path: "/files/data.jsonp", expectedStatus: http.StatusOK, expectedBody: "data"},
+		{path: "/files/data.jsonpp", expectedStatus: http.StatusNotFound, expectedBody: "404 page not found\n"},
+	}
+
+	for _, tt := range tests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		if rr.Code != tt.expectedStatus || rr.Body.String() != tt.expectedBody {
+			t.Errorf("unexpected response for %s: got %d %q want %d %q",
+				tt.path, rr.Code, rr.Body.String(), tt.expectedStatus, tt.expectedBody)
+		}
+	}
+}
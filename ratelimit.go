@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// counts the requests of a route in fixed windows, see HandleFuncRateLimited
+type rateLimiter struct {
+	mu          sync.Mutex
+	limit       int
+	window      time.Duration
+	windowStart time.Time
+	count       int
+}
+
+// reports whether a request at now is within the limit; when it isn't, also how long until the window
+// resets and requests go through again
+func (l *rateLimiter) allow(now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.windowStart) >= l.window {
+		l.windowStart, l.count = now, 0
+	}
+	if l.count < l.limit {
+		l.count++
+		return true, 0
+	}
+	return false, l.windowStart.Add(l.window).Sub(now)
+}
+
+// HandleFuncRateLimited registers a route serving at most limit requests per window, i.e 100 per minute,
+// counted across all clients in fixed windows by the router's time source. Requests over the limit get a
+// 429 with 'Retry-After' telling clients when the window resets. It panics if limit or window isn't
+// positive, like other invalid registrations.
+func (r *customRouter) HandleFuncRateLimited(pattern string, handler http.HandlerFunc, limit int, window time.Duration) {
+	if handler == nil {
+		panic(nilHandlerError(pattern))
+	}
+	if limit <= 0 || window <= 0 {
+		panic(fmt.Errorf("invalid rate limited route '%s': limit %d per %s must be positive", pattern, limit, window))
+	}
+	limiter := &rateLimiter{limit: limit, window: window}
+
+	r.handle(pattern, func(w http.ResponseWriter, req *http.Request) {
+		allowed, wait := limiter.allow(r.now())
+		if !allowed {
+			r.logger().Infof("Rate limited %s %s, %d requests per %s", req.Method, req.URL.Path, limit, window)
+			writeRetryAfter(w, wait)
+			r.writeError(w, http.StatusTooManyRequests, "Too many requests")
+			return
+		}
+		handler(w, req)
+	})
+}
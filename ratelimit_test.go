@@ -0,0 +1,64 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandleFuncRateLimited(t *testing.T) {
+	now := time.Date(2024, time.May, 1, 9, 0, 0, 0, time.UTC)
+	calls := 0
+
+	router := &customRouter{Now: func() time.Time { return now }}
+	router.HandleFuncRateLimited("/search/%s", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}, 2, time.Minute)
+
+	serve := func() *httptest.ResponseRecorder {
+		t.Helper()
+		req, err := http.NewRequest("GET", "/search/q1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	for i := 0; i < 2; i++ {
+		if rr := serve(); rr.Code != http.StatusOK {
+			t.Fatalf("request %d: got status %d want %d", i+1, rr.Code, http.StatusOK)
+		}
+	}
+
+	// over the limit 20.5s into the window: rejected, told to retry once the window resets
+	now = now.Add(20*time.Second + 500*time.Millisecond)
+	rr := serve()
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 over the limit, got %d", rr.Code)
+	}
+	if retryAfter := rr.Header().Get("Retry-After"); retryAfter != "40" {
+		t.Errorf("unexpected Retry-After: got %q want %q", retryAfter, "40")
+	}
+	if calls != 2 {
+		t.Errorf("expected the handler not to be called over the limit, got %d calls", calls)
+	}
+
+	// a new window lets requests through again
+	now = now.Add(40 * time.Second)
+	if rr := serve(); rr.Code != http.StatusOK || rr.Header().Get("Retry-After") != "" {
+		t.Errorf("expected 200 without Retry-After in the next window, got %d %q", rr.Code, rr.Header().Get("Retry-After"))
+	}
+}
+
+func TestHandleFuncRateLimitedInvalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a non-positive limit")
+		}
+	}()
+	router := &customRouter{}
+	router.HandleFuncRateLimited("/search/%s", func(w http.ResponseWriter, r *http.Request) {}, 0, time.Minute)
+}
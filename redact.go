@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// replaces the value of query params listed in RedactQueryKeys
+const redactedValue = "REDACTED"
+
+// returns path with the request's query appended for logging, the values of RedactQueryKeys replaced
+// with REDACTED, i.e "/login?token=REDACTED&next=home"
+func (r *customRouter) logPath(path string, req *http.Request) string {
+	if req.URL.RawQuery == "" {
+		return path
+	}
+	return path + "?" + redactQuery(req.URL.RawQuery, r.RedactQueryKeys)
+}
+
+// returns rawQuery with the value of every param named in keys replaced, keeping the order and encoding
+// of everything else as sent
+func redactQuery(rawQuery string, keys []string) string {
+	if len(keys) == 0 {
+		return rawQuery
+	}
+
+	pairs := strings.Split(rawQuery, "&")
+	for i, pair := range pairs {
+		rawKey, _, _ := strings.Cut(pair, "=")
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			key = rawKey
+		}
+		if slices.Contains(keys, key) {
+			pairs[i] = rawKey + "=" + redactedValue
+		}
+	}
+	return strings.Join(pairs, "&")
+}
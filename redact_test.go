@@ -0,0 +1,63 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedactQueryKeys(t *testing.T) {
+	logger := &capturingLogger{}
+	router := &customRouter{Logger: logger, RedactQueryKeys: []string{"token", "api key"}}
+	router.HandleFunc("/login/%s", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	for _, path := range []string{
+		"/login/web?token=s3cret&next=home",
+		"/missing?next=home&api+key=abc123&token=s3cret",
+	} {
+		req, err := http.NewRequest("GET", path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	logs := strings.Join(logger.messages, "\n")
+	for _, secret := range []string{"s3cret", "abc123"} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("redacted value %q logged: %q", secret, logger.messages)
+		}
+	}
+	for _, expected := range []string{
+		"Matched route '/login/%s' for GET /login/web?token=REDACTED&next=home",
+		"Route '/login/%s' responded 500 for GET /login/web?token=REDACTED&next=home",
+		"No route matched GET /missing?next=home&api+key=REDACTED&token=REDACTED (NoMatch)",
+	} {
+		if !strings.Contains(logs, expected) {
+			t.Errorf("expected a log line %q, got %q", expected, logger.messages)
+		}
+	}
+}
+
+func TestRedactQuery(t *testing.T) {
+	tests := []struct {
+		rawQuery string
+		keys     []string
+		expected string
+	}{
+		{rawQuery: "token=abc&page=2", keys: []string{"token"}, expected: "token=REDACTED&page=2"},
+		{rawQuery: "token=abc&page=2", expected: "token=abc&page=2"},
+		{rawQuery: "token&token=abc", keys: []string{"token"}, expected: "token=REDACTED&token=REDACTED"},
+		{rawQuery: "to%6Ben=abc", keys: []string{"token"}, expected: "to%6Ben=REDACTED"},
+		{rawQuery: "q=a%20b", keys: []string{"token"}, expected: "q=a%20b"},
+	}
+
+	for _, tt := range tests {
+		if result := redactQuery(tt.rawQuery, tt.keys); result != tt.expected {
+			t.Errorf("redactQuery(%q, %q) = %q; want %q", tt.rawQuery, tt.keys, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// statuses Redirect accepts; 307 and 308 preserve the method and body, i.e for migrating POST endpoints
+var redirectStatuses = map[int]bool{
+	http.StatusMovedPermanently:  true,
+	http.StatusFound:             true,
+	http.StatusSeeOther:          true,
+	http.StatusTemporaryRedirect: true,
+	http.StatusPermanentRedirect: true,
+}
+
+// Redirect registers a route answering every method with a redirect to target using status, which must be
+// one of 301, 302, 303, 307 or 308. Each '%s' in target is replaced by the next captured param, i.e
+// Redirect("/old/%s", "/new/%s", http.StatusPermanentRedirect).
+func (r *customRouter) Redirect(pattern, target string, status int) error {
+	if !redirectStatuses[status] {
+		return fmt.Errorf("status %d is not a redirect status", status)
+	}
+
+	r.handle(pattern, func(w http.ResponseWriter, req *http.Request) {
+		http.Redirect(w, req, redirectTarget(req, target), status)
+	}, func(rt *route) {
+		rt.method = anyMethod
+	})
+	return nil
+}
+
+// fills each '%s' in target with the request's params in order; unfilled ones are left as is
+func redirectTarget(req *http.Request, target string) string {
+	var sb strings.Builder
+	index := 1
+	for {
+		before, after, found := strings.Cut(target, "%s")
+		sb.WriteString(before)
+		if !found {
+			return sb.String()
+		}
+		value, ok := getParamOK(req, index)
+		if !ok {
+			sb.WriteString("%s")
+		} else {
+			sb.WriteString(value)
+		}
+		index++
+		target = after
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRedirect(t *testing.T) {
+	router := &customRouter{}
+	if err := router.Redirect("/v1/orders/%s/items/%s", "/v2/orders/%s/items/%s", http.StatusPermanentRedirect); err != nil {
+		t.Fatal(err)
+	}
+	if err := router.Redirect("/old-home", "/home", http.StatusMovedPermanently); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name             string
+		method           string
+		path             string
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{
+			name:             "POST preserved with 308",
+			method:           "POST",
+			path:             "/v1/orders/o1/items/i2",
+			expectedStatus:   http.StatusPermanentRedirect,
+			expectedLocation: "/v2/orders/o1/items/i2",
+		},
+		{
+			name:             "GET with 308",
+			method:           "GET",
+			path:             "/v1/orders/o1/items/i2",
+			expectedStatus:   http.StatusPermanentRedirect,
+			expectedLocation: "/v2/orders/o1/items/i2",
+		},
+		{
+			name:             "static 301",
+			method:           "GET",
+			path:             "/old-home",
+			expectedStatus:   http.StatusMovedPermanently,
+			expectedLocation: "/home",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+			if location := rr.Header().Get("Location"); location != tt.expectedLocation {
+				t.Errorf("unexpected Location header: got %q want %q", location, tt.expectedLocation)
+			}
+		})
+	}
+}
+
+func TestRedirectRejectsNonRedirectStatus(t *testing.T) {
+	for _, status := range []int{http.StatusOK, http.StatusNotModified, http.StatusNotFound} {
+		router := &customRouter{}
+		if err := router.Redirect("/old", "/new", status); err == nil {
+			t.Errorf("expected an error for status %d", status)
+		}
+		if len(router.routes) != 0 {
+			t.Errorf("expected no route registered for status %d", status)
+		}
+	}
+}
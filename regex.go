@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+)
+
+// HandleRegex registers a route using a hand-written regex rather than a template. Every capturing
+// group must participate in a match, otherwise the request fails with a 500 mismatch error.
+func (r *customRouter) HandleRegex(re *regexp.Regexp, handler http.HandlerFunc) {
+	r.HandleRegexN(re, handler, re.NumSubexp())
+}
+
+// HandleRegexN is HandleRegex with an explicit param count, for regexes with optional or helper groups
+// where re.NumSubexp() isn't the number of params that must be captured
+func (r *customRouter) HandleRegexN(re *regexp.Regexp, handler http.HandlerFunc, paramCount int) {
+	if handler == nil {
+		panic(fmt.Errorf("nil handler for regex '%s'", re))
+	}
+	r.addRoute(&route{
+		template:   re.String(),
+		pattern:    re,
+		handler:    handler,
+		paramCount: paramCount,
+	})
+}
+
+// reports whether at least paramCount capturing groups participated in the match
+func (rt *route) hasExpectedParams(captured []bool) bool {
+	if rt.paramCount == 0 {
+		return true
+	}
+
+	participating := 0
+	for _, c := range captured {
+		if c {
+			participating++
+		}
+	}
+	return participating >= rt.paramCount
+}
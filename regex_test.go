@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestHandleRegexN(t *testing.T) {
+	// the trailing '(draft)' group is optional so it doesn't always participate in a match
+	re := regexp.MustCompile(`^/v(?:1|2)/items/([0-9]+)(?:/(draft))?$`)
+	echo := func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "item %s draft=%q\n", getParam(r, 1), getParam(r, 2))
+	}
+
+	tests := []struct {
+		name           string
+		paramCount     int
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "default count with every group participating",
+			paramCount:     -1,
+			path:           "/v1/items/42/draft",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "item 42 draft=\"draft\"\n",
+		},
+		{
+			name:           "default count gives a false 500 without the optional group",
+			paramCount:     -1,
+			path:           "/v2/items/42",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Internal server error: Mismatched capturing groups\n",
+		},
+		{
+			name:           "override prevents the false 500",
+			paramCount:     1,
+			path:           "/v2/items/42",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "item 42 draft=\"\"\n",
+		},
+		{
+			name:           "override still captures the optional group",
+			paramCount:     1,
+			path:           "/v2/items/42/draft",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "item 42 draft=\"draft\"\n",
+		},
+		{
+			name:           "non-matching path",
+			paramCount:     1,
+			path:           "/v3/items/42",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := &customRouter{}
+			if tt.paramCount < 0 {
+				router.HandleRegex(re, echo)
+			} else {
+				router.HandleRegexN(re, echo, tt.paramCount)
+			}
+
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// writes format filled with params in order as a plain text response, i.e the params TemplateHandler
+// passes with the format "user %s did %s". It errors without writing anything if the format doesn't
+// take exactly one argument per param.
+func respondTemplate(w http.ResponseWriter, r *http.Request, params []string, format string) error {
+	if verbs := formatVerbCount(format); verbs != len(params) {
+		return fmt.Errorf("format %q takes %d argument(s) but %d param(s) were given", format, verbs, len(params))
+	}
+
+	args := make([]any, len(params))
+	for i, param := range params {
+		args[i] = param
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, err := fmt.Fprintf(w, format, args...)
+	return err
+}
+
+// returns how many arguments a fmt format consumes: one per verb plus one per '*' width or precision;
+// "%%" consumes none
+func formatVerbCount(format string) int {
+	count := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		// skip flags, width and precision up to the verb
+		for i++; i < len(format); i++ {
+			c := format[i]
+			if c == '*' {
+				count++
+				continue
+			}
+			if c == '+' || c == '-' || c == '#' || c == ' ' || c == '.' || (c >= '0' && c <= '9') {
+				continue
+			}
+			if c != '%' {
+				count++
+			}
+			break
+		}
+	}
+	return count
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRespondTemplate(t *testing.T) {
+	tests := []struct {
+		name         string
+		params       []string
+		format       string
+		expectedBody string
+		expectErr    bool
+	}{
+		{name: "matching verbs", params: []string{"u42", "login"}, format: "user %s did %s", expectedBody: "user u42 did login"},
+		{name: "escaped percent", params: []string{"u42"}, format: "100%% of %q", expectedBody: `100% of "u42"`},
+		{name: "width and flags", params: []string{"ab"}, format: "[%-4s]", expectedBody: "[ab  ]"},
+		{name: "too few verbs", params: []string{"u42", "login"}, format: "user %s", expectErr: true},
+		{name: "too many verbs", params: []string{"u42"}, format: "user %s did %s", expectErr: true},
+		{name: "star width consumes a param", params: []string{"u42"}, format: "%*s", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			err = respondTemplate(rr, req, tt.params, tt.format)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected a verb count mismatch error")
+				}
+				if rr.Body.Len() != 0 {
+					t.Errorf("expected nothing written on error, got %q", rr.Body.String())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Retry-After sent with 503s whose wait isn't known, unless customRouter.RetryAfter is set
+const defaultRetryAfter = 30 * time.Second
+
+// returns the router's fallback Retry-After delay
+func (r *customRouter) retryAfter() time.Duration {
+	if r.RetryAfter <= 0 {
+		return defaultRetryAfter
+	}
+	return r.RetryAfter
+}
+
+// sets 'Retry-After' to d in whole seconds for a 429 or 503 response, rounding up so clients don't retry
+// before the wait is over; a non-positive d sets nothing
+func writeRetryAfter(w http.ResponseWriter, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(int((d+time.Second-1)/time.Second)))
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWriteRetryAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		d        time.Duration
+		expected string
+	}{
+		{name: "whole seconds", d: 30 * time.Second, expected: "30"},
+		{name: "rounded up", d: 1500 * time.Millisecond, expected: "2"},
+		{name: "under a second", d: time.Millisecond, expected: "1"},
+		{name: "minutes", d: 2 * time.Minute, expected: "120"},
+		{name: "zero omitted", d: 0, expected: ""},
+		{name: "negative omitted", d: -time.Second, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			writeRetryAfter(rr, tt.d)
+			if header := rr.Header().Get("Retry-After"); header != tt.expected {
+				t.Errorf("writeRetryAfter(%v) set %q; want %q", tt.d, header, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+)
+
+// returns a copy of the request with its path mapped by rewrite; the result isn't rewritten again
+// so a rewrite producing a path it would itself rewrite can't loop
+func rewritePath(req *http.Request, rewrite func(string) string) *http.Request {
+	rewritten := rewrite(req.URL.Path)
+	if rewritten == req.URL.Path {
+		return req
+	}
+
+	rewrittenReq := req.Clone(req.Context())
+	rewrittenReq.URL.Path = rewritten
+	// the escaped form no longer corresponds to the path
+	rewrittenReq.URL.RawPath = ""
+	return rewrittenReq
+}
+
+// returns a PathRewrite func replacing a leading prefix, i.e prefixRewrite("/v1/", "/v3/")
+func prefixRewrite(from, to string) func(string) string {
+	return func(path string) string {
+		if rest, ok := strings.CutPrefix(path, from); ok {
+			return to + rest
+		}
+		return path
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPathRewrite(t *testing.T) {
+	router := &customRouter{PathRewrite: prefixRewrite("/v1/", "/v3/")}
+	router.HandleFunc("/v3/users/%s", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "user %s via %s\n", getParam(r, 1), r.URL.Path)
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "legacy path rewritten",
+			path:           "/v1/users/u1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "user u1 via /v3/users/u1\n",
+		},
+		{
+			name:           "current path untouched",
+			path:           "/v3/users/u1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "user u1 via /v3/users/u1\n",
+		},
+		{
+			name:           "unrelated path",
+			path:           "/v2/users/u1",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestPathRewriteAppliedOnce(t *testing.T) {
+	calls := 0
+	router := &customRouter{PathRewrite: func(path string) string {
+		calls++
+		// would never terminate if re-applied to its own output
+		return "/a" + path
+	}}
+	router.HandleFunc("/a/start", func(w http.ResponseWriter, r *http.Request) {})
+
+	req, err := http.NewRequest("GET", "/start", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	if calls != 1 {
+		t.Errorf("expected the rewrite to be applied once, got %d calls", calls)
+	}
+}
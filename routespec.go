@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// RouteSpec describes a route for HandleFuncAll
+type RouteSpec struct {
+	Method  string // HTTP method the route serves, GET when empty
+	Pattern string // template, i.e "/users/%s"
+	Handler http.HandlerFunc
+}
+
+// HandleFuncAll registers every spec that compiles and returns an error for each one that doesn't,
+// so all problems in a route table surface at once instead of panicking on the first
+func (r *customRouter) HandleFuncAll(specs []RouteSpec) []error {
+	var errs []error
+	for i, spec := range specs {
+		rt, err := r.compileRoute(spec.Pattern, spec.Handler)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("route %d (%s %s): %w", i+1, spec.Method, spec.Pattern, err))
+			continue
+		}
+		rt.method = strings.ToUpper(spec.Method)
+		r.addRoute(rt)
+	}
+	return errs
+}
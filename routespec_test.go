@@ -0,0 +1,51 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleFuncAll(t *testing.T) {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	router := &customRouter{}
+	errs := router.HandleFuncAll([]RouteSpec{
+		{Pattern: "/users/%s", Handler: noop},
+		{Method: "GET", Pattern: "{@undefined}/orders/%s", Handler: noop},
+		{Method: "delete", Pattern: "/orders/%s", Handler: noop},
+	})
+
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %d: %v", len(errs), errs)
+	}
+	if len(router.routes) != 2 {
+		t.Fatalf("expected the 2 valid routes registered, got %d", len(router.routes))
+	}
+
+	tests := []struct {
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{method: "GET", path: "/users/u1", expectedStatus: http.StatusOK},
+		{method: "DELETE", path: "/orders/o1", expectedStatus: http.StatusOK},
+		{method: "GET", path: "/orders/o1", expectedStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+		})
+	}
+}
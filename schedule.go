@@ -0,0 +1,37 @@
+package main
+
+import (
+	"net/http"
+	"time"
+)
+
+// returns the current time from the router's time source
+func (r *customRouter) now() time.Time {
+	if r.Now == nil {
+		return time.Now()
+	}
+	return r.Now()
+}
+
+// HandleFuncScheduled registers a route that only serves within [activeFrom, activeUntil), i.e around a
+// maintenance window. Outside the window it responds 503; before the window opens 'Retry-After' tells
+// clients when to come back, after it closes it's the router's RetryAfter fallback.
+func (r *customRouter) HandleFuncScheduled(pattern string, handler http.HandlerFunc, activeFrom, activeUntil time.Time) {
+	if handler == nil {
+		panic(nilHandlerError(pattern))
+	}
+	r.handle(pattern, func(w http.ResponseWriter, req *http.Request) {
+		now := r.now()
+		if now.Before(activeFrom) {
+			writeRetryAfter(w, activeFrom.Sub(now))
+			r.writeError(w, http.StatusServiceUnavailable, "Service unavailable")
+			return
+		}
+		if !now.Before(activeUntil) {
+			writeRetryAfter(w, r.retryAfter())
+			r.writeError(w, http.StatusServiceUnavailable, "Service unavailable")
+			return
+		}
+		handler(w, req)
+	})
+}
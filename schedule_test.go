@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandleFuncScheduled(t *testing.T) {
+	activeFrom := time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC)
+	activeUntil := activeFrom.Add(2 * time.Hour)
+
+	tests := []struct {
+		name               string
+		now                time.Time
+		expectedStatus     int
+		expectedRetryAfter string
+	}{
+		{
+			name:               "before the window",
+			now:                activeFrom.Add(-90 * time.Second),
+			expectedStatus:     http.StatusServiceUnavailable,
+			expectedRetryAfter: "90",
+		},
+		{
+			name:               "retry after rounds up",
+			now:                activeFrom.Add(-1500 * time.Millisecond),
+			expectedStatus:     http.StatusServiceUnavailable,
+			expectedRetryAfter: "2",
+		},
+		{
+			name:           "start of the window",
+			now:            activeFrom,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "during the window",
+			now:            activeFrom.Add(time.Hour),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:               "after the window",
+			now:                activeUntil,
+			expectedStatus:     http.StatusServiceUnavailable,
+			expectedRetryAfter: "30",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := &customRouter{Now: func() time.Time { return tt.now }}
+			router.HandleFuncScheduled("/migrate/%s", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, "migrating %s\n", getParam(r, 1))
+			}, activeFrom, activeUntil)
+
+			req, err := http.NewRequest("GET", "/migrate/db1", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			if got := rr.Header().Get("Retry-After"); got != tt.expectedRetryAfter {
+				t.Errorf("unexpected Retry-After header: got %q want %q", got, tt.expectedRetryAfter)
+			}
+		})
+	}
+}
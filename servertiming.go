@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// context key for the request's serverTiming, see serverTimingFrom
+type serverTimingKey struct{}
+
+// accumulates named phase durations for a 'Server-Timing' header, i.e "db;dur=12.3, render;dur=4"
+type serverTiming struct {
+	mu        sync.Mutex
+	names     []string
+	durations map[string]time.Duration
+}
+
+func newServerTiming() *serverTiming {
+	return &serverTiming{durations: make(map[string]time.Duration)}
+}
+
+// adds d to the named phase; a nil serverTiming ignores it so handlers needn't check it's enabled
+func (t *serverTiming) Record(name string, d time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.durations[name]; !ok {
+		t.names = append(t.names, name)
+	}
+	t.durations[name] += d
+}
+
+// returns the 'Server-Timing' header value with durations in milliseconds, phases in the order first recorded
+func (t *serverTiming) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	metrics := make([]string, 0, len(t.names))
+	for _, name := range t.names {
+		ms := float64(t.durations[name].Round(100*time.Microsecond)) / float64(time.Millisecond)
+		metrics = append(metrics, name+";dur="+strconv.FormatFloat(ms, 'f', -1, 64))
+	}
+	return strings.Join(metrics, ", ")
+}
+
+// returns the request's serverTiming, nil if EnableServerTiming wasn't called
+func serverTimingFrom(r *http.Request) *serverTiming {
+	timing, _ := r.Context().Value(serverTimingKey{}).(*serverTiming)
+	return timing
+}
+
+// EnableServerTiming adds middleware giving every matched request a serverTiming, available via
+// serverTimingFrom, and sending the phases recorded on it as a 'Server-Timing' header. The header goes
+// out with the response headers so only phases recorded before the handler starts writing are included.
+func (r *customRouter) EnableServerTiming() {
+	r.use(func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, req *http.Request) {
+			timing := newServerTiming()
+			tw := &timingWriter{ResponseWriter: w, timing: timing}
+			next(tw, req.WithContext(context.WithValue(req.Context(), serverTimingKey{}, timing)))
+			// a handler that never wrote still gets its header with the implicit 200
+			tw.setHeader()
+		}
+	})
+}
+
+// sets the 'Server-Timing' header just before the response headers are written
+type timingWriter struct {
+	http.ResponseWriter
+	timing *serverTiming
+	sent   bool
+}
+
+func (tw *timingWriter) setHeader() {
+	if tw.sent {
+		return
+	}
+	tw.sent = true
+	if header := tw.timing.String(); header != "" {
+		tw.Header().Set("Server-Timing", header)
+	}
+}
+
+func (tw *timingWriter) WriteHeader(status int) {
+	tw.setHeader()
+	tw.ResponseWriter.WriteHeader(status)
+}
+
+func (tw *timingWriter) Write(b []byte) (int, error) {
+	tw.setHeader()
+	return tw.ResponseWriter.Write(b)
+}
+
+// allows http.ResponseController to reach the underlying writer, i.e to flush
+func (tw *timingWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
+
+// passes a hijack through for handlers that type-assert the writer, i.e WebSocket upgraders; the
+// connection is the handler's from then on so no header is set afterwards
+func (tw *timingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := tw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T doesn't support hijacking: %w", tw.ResponseWriter, http.ErrNotSupported)
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err == nil {
+		tw.sent = true
+	}
+	return conn, rw, err
+}
+
+// passes a flush through for handlers that type-assert the writer, i.e to stream a response; flushing
+// sends the headers so the 'Server-Timing' header is set first
+func (tw *timingWriter) Flush() {
+	flusher, ok := tw.ResponseWriter.(http.Flusher)
+	if !ok {
+		return
+	}
+	tw.setHeader()
+	flusher.Flush()
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestServerTiming(t *testing.T) {
+	router := &customRouter{}
+	router.EnableServerTiming()
+	router.HandleFunc("/reports/%s", func(w http.ResponseWriter, r *http.Request) {
+		timing := serverTimingFrom(r)
+		timing.Record("db", 12300*time.Microsecond)
+		timing.Record("cache", 2*time.Millisecond)
+		timing.Record("cache", 500*time.Microsecond)
+		fmt.Fprintln(w, "report", getParam(r, 1))
+		// recorded after the headers were sent so it can't be reported
+		timing.Record("late", time.Millisecond)
+	})
+	router.HandleFunc("/empty/%s", func(w http.ResponseWriter, r *http.Request) {
+		serverTimingFrom(r).Record("auth", 750*time.Microsecond)
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedHeader string
+	}{
+		{name: "phases accumulated in order", path: "/reports/r1", expectedHeader: "db;dur=12.3, cache;dur=2.5"},
+		{name: "handler that never writes", path: "/empty/e1", expectedHeader: "auth;dur=0.8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if header := rr.Header().Get("Server-Timing"); header != tt.expectedHeader {
+				t.Errorf("unexpected Server-Timing header: got %q want %q", header, tt.expectedHeader)
+			}
+		})
+	}
+}
+
+func TestServerTimingDisabled(t *testing.T) {
+	router := &customRouter{}
+	router.HandleFunc("/reports/%s", func(w http.ResponseWriter, r *http.Request) {
+		// no-op without EnableServerTiming
+		serverTimingFrom(r).Record("db", time.Millisecond)
+	})
+
+	req, err := http.NewRequest("GET", "/reports/r1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if header := rr.Header().Get("Server-Timing"); header != "" {
+		t.Errorf("expected no Server-Timing header, got %q", header)
+	}
+}
+
+func TestServerTimingPassesThroughHijackAndFlush(t *testing.T) {
+	router := &customRouter{}
+	router.EnableServerTiming()
+	router.HandleWS("/ws/%s", func(params []string, w http.ResponseWriter, r *http.Request) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("unexpected error hijacking through the timing writer: %v", err)
+			return
+		}
+		conn.Close()
+	})
+	router.HandleFunc("/stream/%s", func(w http.ResponseWriter, r *http.Request) {
+		serverTimingFrom(r).Record("db", 2*time.Millisecond)
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			t.Errorf("%T isn't an http.Flusher", w)
+			return
+		}
+		flusher.Flush()
+	})
+
+	req, err := http.NewRequest("GET", "/ws/lobby", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	hijackable := newHijackableRecorder()
+	defer hijackable.client.Close()
+	router.ServeHTTP(hijackable, req)
+
+	req, err = http.NewRequest("GET", "/stream/s1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if !rr.Flushed {
+		t.Error("expected the flush to reach the underlying writer")
+	}
+	if got := rr.Header().Get("Server-Timing"); got != "db;dur=2" {
+		t.Errorf("unexpected Server-Timing header: got %q want %q", got, "db;dur=2")
+	}
+}
@@ -0,0 +1,11 @@
+package main
+
+import "strings"
+
+// removes a single trailing slash, leaving the root path "/" as-is
+func trimTrailingSlash(path string) string {
+	if len(path) > 1 && strings.HasSuffix(path, "/") {
+		return path[:len(path)-1]
+	}
+	return path
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTrimTrailingSlash(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "/foo/bar/", expected: "/foo/bar"},
+		{path: "/foo/bar", expected: "/foo/bar"},
+		{path: "/foo/bar//", expected: "/foo/bar/"},
+		{path: "/", expected: "/"},
+		{path: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		if result := trimTrailingSlash(tt.path); result != tt.expected {
+			t.Errorf("trimTrailingSlash(%q) = %q; want %q", tt.path, result, tt.expected)
+		}
+	}
+}
+
+func TestIgnoreTrailingSlash(t *testing.T) {
+	tests := []struct {
+		name           string
+		template       string
+		path           string
+		ignore         bool
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "request without slash",
+			template:       "/foo/%s/bar",
+			path:           "/foo/abc/bar",
+			ignore:         true,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "matched abc\n",
+		},
+		{
+			name:           "request with slash",
+			template:       "/foo/%s/bar",
+			path:           "/foo/abc/bar/",
+			ignore:         true,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "matched abc\n",
+		},
+		{
+			name:           "template with slash, request without",
+			template:       "/foo/%s/bar/",
+			path:           "/foo/abc/bar",
+			ignore:         true,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "matched abc\n",
+		},
+		{
+			name:           "only a single slash is ignored",
+			template:       "/foo/%s/bar",
+			path:           "/foo/abc/bar//",
+			ignore:         true,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+		{
+			name:           "disabled by default",
+			template:       "/foo/%s/bar",
+			path:           "/foo/abc/bar/",
+			ignore:         false,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := &customRouter{IgnoreTrailingSlash: tt.ignore}
+			router.HandleFunc(tt.template, func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, "matched %s\n", getParam(r, 1))
+			})
+
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v",
+					status, tt.expectedStatus)
+			}
+
+			if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %q want %q",
+					rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// name of the catch-all param a stdlib prefix pattern like "/static/" is given
+const stdlibRemainderName = "remainder"
+
+// translates a Go 1.22 ServeMux path pattern into a template matching the same paths, so existing
+// stdlib style patterns can be reused with this router:
+//
+//	{name}      any single segment, captured by name
+//	{name...}   the rest of the path, possibly empty, captured by name
+//	{$}         the end of the path, so "/foo/{$}" only matches "/foo/"
+//	trailing /  a prefix match like the stdlib's, the rest of the path is captured as "remainder"
+//
+// Static segments are quoted, since the stdlib matches them literally, so "/v1.0/{id}" doesn't match
+// "/v1x0/a". Only the path is translated; a method or host in front of it isn't supported.
+func fromStdlibPattern(pattern string) string {
+	segments := strings.Split(pattern, "/")
+	last := len(segments) - 1
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			segments[i] = regexp.QuoteMeta(segment)
+			continue
+		}
+
+		name := segment[1 : len(segment)-1]
+		switch {
+		case name == "$" && i == last:
+			segments[i] = ""
+		case strings.HasSuffix(name, "...") && i == last:
+			segments[i] = "{" + strings.TrimSuffix(name, "...") + ":.*}"
+		case paramNameRegex.MatchString(name):
+			segments[i] = "{" + name + ":[^/]+}"
+		}
+	}
+
+	// without "{$}" a pattern ending in a slash matches every path below it
+	if len(segments) > 1 && segments[last] == "" && !strings.HasSuffix(pattern, "{$}") {
+		segments[last] = "{" + stdlibRemainderName + ":.*}"
+	}
+	return strings.Join(segments, "/")
+}
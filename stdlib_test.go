@@ -0,0 +1,107 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFromStdlibPattern(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		expected string
+	}{
+		{pattern: "/foo/{id}/baz/{other}", expected: "/foo/{id:[^/]+}/baz/{other:[^/]+}"},
+		{pattern: "/files/{path...}", expected: "/files/{path:.*}"},
+		{pattern: "/exact/{$}", expected: "/exact/"},
+		{pattern: "/static/", expected: "/static/{remainder:.*}"},
+		{pattern: "/", expected: "/{remainder:.*}"},
+		{pattern: "/health", expected: "/health"},
+		{pattern: "/v1.0/{id}", expected: `/v1\.0/{id:[^/]+}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if result := fromStdlibPattern(tt.pattern); result != tt.expected {
+				t.Errorf("fromStdlibPattern(%q) = %q; want %q", tt.pattern, result, tt.expected)
+			}
+		})
+	}
+}
+
+// the translated template must match exactly the paths ServeMux matches, capturing the same values
+func TestFromStdlibPatternMatchesLikeServeMux(t *testing.T) {
+	tests := []struct {
+		pattern string
+		names   []string
+		paths   []string
+	}{
+		{
+			pattern: "/foo/{id}/baz/{other}",
+			names:   []string{"id", "other"},
+			paths:   []string{"/foo/a-1.b/baz/x_y", "/foo/a/baz/", "/foo/a/baz/b/c", "/foo/a/qux/b"},
+		},
+		{
+			pattern: "/files/{path...}",
+			names:   []string{"path"},
+			paths:   []string{"/files/a/b/c.txt", "/files/", "/files/x", "/file"},
+		},
+		{
+			pattern: "/exact/{$}",
+			paths:   []string{"/exact/", "/exact/more"},
+		},
+		{
+			pattern: "/v1.0/{id}",
+			names:   []string{"id"},
+			paths:   []string{"/v1.0/a", "/v1x0/a"},
+		},
+		{
+			pattern: "/static/",
+			paths:   []string{"/static/", "/static/css/app.css", "/statics"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			var stdlibParams, routerParams []string
+			mux := http.NewServeMux()
+			mux.HandleFunc(tt.pattern, func(w http.ResponseWriter, r *http.Request) {
+				for _, name := range tt.names {
+					stdlibParams = append(stdlibParams, r.PathValue(name))
+				}
+			})
+			router := &customRouter{}
+			router.HandleFunc(fromStdlibPattern(tt.pattern), func(w http.ResponseWriter, r *http.Request) {
+				for _, name := range tt.names {
+					routerParams = append(routerParams, getParamByName(r, name))
+				}
+			})
+
+			for _, path := range tt.paths {
+				stdlibParams, routerParams = nil, nil
+				req, err := http.NewRequest("GET", path, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				muxRR, routerRR := httptest.NewRecorder(), httptest.NewRecorder()
+				mux.ServeHTTP(muxRR, req)
+				router.ServeHTTP(routerRR, req)
+
+				if muxRR.Code != routerRR.Code {
+					t.Errorf("%s: ServeMux responded %d, router %d", path, muxRR.Code, routerRR.Code)
+				}
+				if len(stdlibParams) != len(routerParams) {
+					t.Errorf("%s: ServeMux captured %q, router %q", path, stdlibParams, routerParams)
+					continue
+				}
+				for i := range stdlibParams {
+					if stdlibParams[i] != routerParams[i] {
+						t.Errorf("%s: ServeMux captured %q, router %q", path, stdlibParams, routerParams)
+						break
+					}
+				}
+			}
+		})
+	}
+}
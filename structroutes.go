@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// RegisterStruct registers a route for every field of the struct x (or pointer to one) with a `route`
+// tag like `route:"GET /users/{id}"`; the method is optional and defaults to GET. Go has no tags on
+// methods, so a tagged field either is the handler, a func(http.ResponseWriter, *http.Request), or names
+// an exported method of x with that signature in a `handler` tag:
+//
+//	type userAPI struct {
+//		_ struct{} `route:"GET /users/{id}" handler:"GetUser"`
+//		_ struct{} `route:"POST /users" handler:"CreateUser"`
+//	}
+//
+// Like HandleFuncAll every valid route is registered and the problems with the rest are returned joined.
+func (r *customRouter) RegisterStruct(x any) error {
+	value := reflect.ValueOf(x)
+	structValue := reflect.Indirect(value)
+	if structValue.Kind() != reflect.Struct {
+		return fmt.Errorf("RegisterStruct needs a struct or pointer to one, got %T", x)
+	}
+
+	var specs []RouteSpec
+	var errs []error
+	structType := structValue.Type()
+	for i := range structType.NumField() {
+		field := structType.Field(i)
+		tag, ok := field.Tag.Lookup("route")
+		if !ok {
+			continue
+		}
+
+		method, pattern, hasMethod := strings.Cut(strings.TrimSpace(tag), " ")
+		if !hasMethod {
+			method, pattern = "", method
+		}
+
+		handler, err := structHandler(value, structValue, field)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("field %s (%s): %w", field.Name, tag, err))
+			continue
+		}
+		specs = append(specs, RouteSpec{Method: method, Pattern: strings.TrimSpace(pattern), Handler: handler})
+	}
+
+	errs = append(errs, r.HandleFuncAll(specs)...)
+	return errors.Join(errs...)
+}
+
+// returns the handler for a tagged field: the method named by its `handler` tag, looked up on value so
+// pointer receiver methods are found too, or else the field itself
+func structHandler(value, structValue reflect.Value, field reflect.StructField) (http.HandlerFunc, error) {
+	if name, ok := field.Tag.Lookup("handler"); ok {
+		method := value.MethodByName(name)
+		if !method.IsValid() {
+			return nil, fmt.Errorf("no exported method %s", name)
+		}
+		handler, ok := asHandlerFunc(method)
+		if !ok {
+			return nil, fmt.Errorf("method %s isn't a func(http.ResponseWriter, *http.Request)", name)
+		}
+		return handler, nil
+	}
+
+	if !field.IsExported() {
+		return nil, errors.New("unexported field without a handler tag")
+	}
+	handler, ok := asHandlerFunc(structValue.FieldByIndex(field.Index))
+	if !ok {
+		return nil, errors.New("field isn't a func(http.ResponseWriter, *http.Request)")
+	}
+	return handler, nil
+}
+
+func asHandlerFunc(v reflect.Value) (http.HandlerFunc, bool) {
+	switch fn := v.Interface().(type) {
+	case func(http.ResponseWriter, *http.Request):
+		return fn, fn != nil
+	case http.HandlerFunc:
+		return fn, fn != nil
+	}
+	return nil, false
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type userAPI struct {
+	prefix string
+
+	_ struct{} `route:"GET /users/{id}" handler:"GetUser"`
+	_ struct{} `route:"POST /users" handler:"CreateUser"`
+
+	Health http.HandlerFunc `route:"/health"`
+}
+
+func (api *userAPI) GetUser(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintf(w, "%s get %s", api.prefix, getParamByName(r, "id"))
+}
+
+func (api *userAPI) CreateUser(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusCreated)
+	fmt.Fprintf(w, "%s created", api.prefix)
+}
+
+func TestRegisterStruct(t *testing.T) {
+	router := &customRouter{}
+	api := &userAPI{prefix: "api", Health: func(w http.ResponseWriter, r *http.Request) { fmt.Fprint(w, "ok") }}
+	if err := router.RegisterStruct(api); err != nil {
+		t.Fatalf("unexpected error registering the struct: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "tagged GET method", method: "GET", path: "/users/u42", expectedStatus: http.StatusOK, expectedBody: "api get u42"},
+		{name: "tagged POST method", method: "POST", path: "/users", expectedStatus: http.StatusCreated, expectedBody: "api created"},
+		{name: "handler field defaults to GET", method: "GET", path: "/health", expectedStatus: http.StatusOK, expectedBody: "ok"},
+		{name: "method not registered", method: "DELETE", path: "/users/u42", expectedStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+			if tt.expectedBody != "" && rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+// struct with a tagged field of each invalid kind and one valid field
+type badAPI struct {
+	_       struct{}         `route:"GET /missing" handler:"Missing"`
+	_       struct{}         `route:"GET /wrong" handler:"Wrong"`
+	Nothing string           `route:"GET /nothing"`
+	Nil     http.HandlerFunc `route:"GET /nil"`
+	Fine    http.HandlerFunc `route:"GET /fine"`
+}
+
+func (badAPI) Wrong() {}
+
+func TestRegisterStructErrors(t *testing.T) {
+
+	router := &customRouter{}
+	err := router.RegisterStruct(badAPI{Fine: func(w http.ResponseWriter, r *http.Request) {}})
+	if err == nil {
+		t.Fatal("expected errors for the invalid fields")
+	}
+	for _, expected := range []string{"no exported method Missing", "method Wrong isn't a func", "field Nothing", "field Nil"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected the error to mention %q, got %v", expected, err)
+		}
+	}
+	if len(router.routes) != 1 || router.routes[0].template != "/fine" {
+		t.Errorf("expected only the valid field registered, got %d routes", len(router.routes))
+	}
+
+	if err := router.RegisterStruct(42); err == nil {
+		t.Error("expected an error registering a non-struct")
+	}
+}
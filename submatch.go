@@ -0,0 +1,41 @@
+package main
+
+import "sync"
+
+// reusable results of a regex match, so matching a request doesn't allocate the params slices every time.
+// regexp has no way to fill a caller's index buffer, so only the slices built from the indices are pooled.
+type submatches struct {
+	values   []string // like regexp.FindStringSubmatch, the full match then each group
+	captured []bool   // whether each group participated in the match
+}
+
+var submatchPool = sync.Pool{New: func() any { return &submatches{} }}
+
+// returns a buffer from the pool, to be handed back with release once its results are no longer used
+func acquireSubmatches() *submatches {
+	return submatchPool.Get().(*submatches)
+}
+
+func (s *submatches) release() {
+	// drop references to the matched strings so the pool doesn't keep request paths alive
+	clear(s.values)
+	submatchPool.Put(s)
+}
+
+// sets the results from the indices regexp.FindStringSubmatchIndex returned for s
+func (s *submatches) fill(str string, indices []int) {
+	n := len(indices) / 2
+	if cap(s.values) < n {
+		s.values = make([]string, n)
+		s.captured = make([]bool, n)
+	}
+	s.values, s.captured = s.values[:n], s.captured[:n]
+	for i := range n {
+		start := indices[2*i]
+		s.captured[i] = start >= 0
+		s.values[i] = ""
+		if start >= 0 {
+			s.values[i] = str[start:indices[2*i+1]]
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestMatchIntoAgreesWithFindStringSubmatch(t *testing.T) {
+	router := &customRouter{}
+	for _, template := range []string{
+		"/foo/bar/%s/baz/%s/qux",
+		"/users/{userID}/posts/%d",
+		"(/v%d)?/items/%s",
+		"/health",
+	} {
+		router.HandleFunc(template, func(w http.ResponseWriter, r *http.Request) {})
+	}
+	router.HandleRegex(regexp.MustCompile(`^/opt/([a-z]*)(?:/(x))?$`), func(w http.ResponseWriter, r *http.Request) {})
+
+	paths := []string{
+		"/foo/bar/123/baz/456/qux",
+		"/users/u1/posts/7",
+		"/v2/items/abc",
+		"/items/abc",
+		"/health",
+		"/opt/",
+		"/opt/ab/x",
+		"/missing",
+	}
+
+	// one buffer reused for every match, as ServeHTTP does
+	buf := acquireSubmatches()
+	defer buf.release()
+	for _, rt := range router.routes {
+		for _, path := range paths {
+			expected := rt.pattern.FindStringSubmatch(path)
+			values, captured := rt.matchInto(path, buf)
+			if !reflect.DeepEqual(values, expected) {
+				t.Errorf("%s on %q: matchInto = %q; FindStringSubmatch = %q", rt.template, path, values, expected)
+			}
+			if values == nil {
+				continue
+			}
+
+			indices := rt.pattern.FindStringSubmatchIndex(path)
+			for i := range captured {
+				if captured[i] != (indices[2*i] >= 0) {
+					t.Errorf("%s on %q: group %d captured = %v", rt.template, path, i, captured[i])
+				}
+			}
+		}
+	}
+}
+
+func TestPooledHandlerParams(t *testing.T) {
+	handler := newPathRegexHandler("/foo/bar/%s/baz/%s/qux")
+	for _, path := range []string{"/foo/bar/a/baz/b/qux", "/foo/bar/longer1/baz/longer2/qux", "/foo/bar/c/baz/d/qux"} {
+		req, err := http.NewRequest("GET", path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+
+		matches := regexp.MustCompile(makeRegexPatternStr("/foo/bar/%s/baz/%s/qux")).FindStringSubmatch(path)
+		expected := "Parameter 1: " + matches[1] + "\nParameter 2: " + matches[2] + "\n"
+		if rr.Body.String() != expected {
+			t.Errorf("%s: got %q want %q", path, rr.Body.String(), expected)
+		}
+	}
+}
+
+func BenchmarkSubmatch(b *testing.B) {
+	rt, err := (&customRouter{}).compileRoute("/foo/bar/%s/baz/%s/qux", func(w http.ResponseWriter, r *http.Request) {})
+	if err != nil {
+		b.Fatal(err)
+	}
+	const path = "/foo/bar/123/baz/456/qux"
+
+	b.Run("FindStringSubmatch", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			if rt.pattern.FindStringSubmatch(path) == nil {
+				b.Fatal("no match")
+			}
+		}
+	})
+
+	b.Run("match", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			if values, _ := rt.match(path); values == nil {
+				b.Fatal("no match")
+			}
+		}
+	})
+
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			buf := acquireSubmatches()
+			if values, _ := rt.matchInto(path, buf); values == nil {
+				b.Fatal("no match")
+			}
+			buf.release()
+		}
+	})
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// regex class used for a single '%s' path parameter
+const defaultParamClass = "[a-zA-Z0-9]+"
+
+// largest N accepted by a '{name:N}' placeholder, bounded by the regexp package's maximum repeat count
+const maxSegmentCount = 1000
+
+// valid names for '{name}' placeholders; these become regex group names so must be word characters
+var paramNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// settings that change how a template is compiled; the zero value compiles '/' delimited paths
+type templateOptions struct {
+	delimiter   byte // separates segments, i.e '.' for topic names like "a.b.c"; 0 means '/'
+	structural  bool // accept any segment for each param, ignoring its class
+	foldStatic  bool // match the static text of the template ignoring ASCII case, see foldStaticASCII
+	quoteStatic bool // match the static text literally rather than as regex, i.e the dots of a host name
+
+	// classes[i] replaces the class of the template's i-th '%s' unless empty, see HandleFuncClasses
+	classes []string
+}
+
+func (o templateOptions) segmentDelimiter() byte {
+	if o.delimiter == 0 {
+		return '/'
+	}
+	return o.delimiter
+}
+
+// whether static text is matched literally; it always is with a custom delimiter, which is likely regex
+// syntax itself, i.e the dots of "a.%s.c"
+func (o templateOptions) quotesStatic() bool {
+	return o.quoteStatic || o.segmentDelimiter() != '/'
+}
+
+// regex class matching a single segment; with a custom delimiter this is anything but the delimiter
+func (o templateOptions) paramClass() string {
+	if o.segmentDelimiter() == '/' && !o.structural {
+		return defaultParamClass
+	}
+	return "[^" + regexp.QuoteMeta(string(o.segmentDelimiter())) + "]+"
+}
+
+// regex class for '%t'; it's permissive, i.e "2023-02-30" matches, with precise validation done by parsing
+func (o templateOptions) dateClass() string {
+	if o.structural {
+		return o.paramClass()
+	}
+	return dateParamClass
+}
+
+// regex class matching a single numeric segment, or any segment when matching structurally
+func (o templateOptions) numericClass() string {
+	if o.structural {
+		return o.paramClass()
+	}
+	return "[0-9]+"
+}
+
+// ParamInfo describes a path param declared by a route template, i.e for generating API docs
+type ParamInfo struct {
+	Index int    // 1-indexed position, as used by getParam
+	Name  string // name of a '{name}' placeholder, empty for positional params
+	Class string // regex the param's value must match, i.e "[a-zA-Z0-9]+"
+	Verb  byte   // verb of a positional param, i.e 's' for '%s'; 0 for placeholders like '{name}'
+
+	// the param is an enum matched ignoring case whose value is stored lowercased, i.e "{format:pdf|csv,ci}"
+	CaseInsensitive bool
+	// the param may be absent from a matching path, i.e the version of an optional "(/v%d)?" prefix
+	Optional bool
+}
+
+// converts a route template to an (unanchored) regex string. Supported placeholders:
+//
+//	%s             a single alphanumeric segment, i.e "/users/%s"
+//	%d             a single numeric segment, i.e "/orders/%d"
+//	%t             a date segment like "2023-01-15", checked to be a real date when routing
+//	{name}         a single alphanumeric segment retrievable by name, i.e "/users/{userID}"
+//	{name:N}       exactly N segments captured as one param, i.e "/geo/{coords:2}" matches "/geo/45.0/-93.0"
+//	{name:regex}   a param matching a custom class without capturing groups, i.e "/posts/{slug:[a-z-]+}"
+//	{name:a|b,ci}  an enum matched ignoring case, stored lowercased, i.e "/export/{format:pdf|csv,ci}"
+//
+// the query part of a route template, from a '?' followed by a key and '=' as in "/search?type=%s", is
+// split off before this, see splitQueryTemplate
+//
+// a template may also start with an optional version segment, "(/v%d)?/resource/%s" matching both
+// "/v3/resource/x" and "/resource/x", with the version captured as the "version" param when present
+//
+// anything else is passed through as regex syntax, as it always has been, so "/files/%s\\.json" needs
+// its dot escaped to only match "/files/x.json"; this includes placeholders that aren't valid, which are
+// left as written. With a custom delimiter the static text is matched literally instead, so "a.%s.c"
+// doesn't match "aXxXc"
+func templateToRegex(template string, opts templateOptions) string {
+	regexStr, _ := compileTemplateParams(template, opts)
+	return regexStr
+}
+
+// like templateToRegex but also returns the params the template declares, in order
+func compileTemplateParams(template string, opts templateOptions) (string, []ParamInfo) {
+	var sb strings.Builder
+	var params []ParamInfo
+	walkTemplate(template, opts, func(static string) {
+		if opts.quotesStatic() {
+			static = regexp.QuoteMeta(static)
+		}
+		sb.WriteString(static)
+	}, func(param ParamInfo, group string) {
+		sb.WriteString(group)
+		params = append(params, param)
+	})
+	if opts.foldStatic {
+		return foldStaticASCII(sb.String()), params
+	}
+	return sb.String(), params
+}
+
+// splits a template into static text and params, calling static for each run of literal text and param
+// for each placeholder with its 1-indexed ParamInfo and the capturing group matching it
+func walkTemplate(template string, opts templateOptions, static func(string), param func(ParamInfo, string)) {
+	count := 0
+	addParam := func(p ParamInfo, group string) {
+		count++
+		p.Index = count
+		param(p, group)
+	}
+
+	i := 0
+	if strings.HasPrefix(template, optionalVersionPrefix) {
+		class := opts.numericClass()
+		addParam(ParamInfo{Name: versionParamName, Class: class, Optional: true},
+			fmt.Sprintf("(?:/v(?P<%s>%s))?", versionParamName, class))
+		i += len(optionalVersionPrefix)
+	}
+
+	// '%s' placeholders seen so far, indexing opts.classes
+	positional := 0
+
+	// start of the literal text not yet passed to static
+	literal := i
+	flush := func(end int) {
+		if end > literal {
+			static(template[literal:end])
+		}
+	}
+
+	for i < len(template) {
+		var class string
+		switch {
+		case strings.HasPrefix(template[i:], "%s"):
+			class = opts.paramClass()
+			if positional < len(opts.classes) && opts.classes[positional] != "" && !opts.structural {
+				class = opts.classes[positional]
+			}
+			positional++
+		case strings.HasPrefix(template[i:], "%d"):
+			class = opts.numericClass()
+		case strings.HasPrefix(template[i:], "%t"):
+			class = opts.dateClass()
+		case template[i] == '{':
+			end := closingBrace(template[i:])
+			if end == -1 {
+				// unterminated, the rest is literal
+				i = len(template)
+				continue
+			}
+			if p, ok := placeholderParam(template[i+1:i+end], opts); ok {
+				flush(i)
+				addParam(p, fmt.Sprintf("(?P<%s>%s)", p.Name, p.Class))
+				literal = i + end + 1
+			}
+			i += end + 1
+			continue
+		default:
+			i++
+			continue
+		}
+
+		// a positional placeholder, all two characters long
+		flush(i)
+		addParam(ParamInfo{Class: class, Verb: template[i+1]}, "("+class+")")
+		i += 2
+		literal = i
+	}
+	flush(len(template))
+}
+
+// returns the index of the '}' closing the '{' s starts with, skipping nested braces and escaped
+// characters so custom classes like "[0-9]{3}" fit in a placeholder; -1 if it's never closed
+func closingBrace(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// returns the param declared by the body of a '{...}' placeholder, reporting false if it isn't one we support
+func placeholderParam(placeholder string, opts templateOptions) (ParamInfo, bool) {
+	name, spec, hasSpec := strings.Cut(placeholder, ":")
+	if !paramNameRegex.MatchString(name) {
+		return ParamInfo{}, false
+	}
+	if !hasSpec {
+		return ParamInfo{Name: name, Class: opts.paramClass()}, true
+	}
+
+	if isDigits(spec) {
+		count, err := strconv.Atoi(spec)
+		if err != nil || count < 1 || count > maxSegmentCount {
+			return ParamInfo{}, false
+		}
+		// N segments are N-1 "segment<delimiter>" pairs followed by a final segment
+		delim := regexp.QuoteMeta(string(opts.segmentDelimiter()))
+		return ParamInfo{Name: name, Class: fmt.Sprintf("(?:[^%s]+%s){%d}[^%s]+", delim, delim, count-1, delim)}, true
+	}
+
+	if options, ok := strings.CutSuffix(spec, ",ci"); ok {
+		class, ok := caseInsensitiveEnumClass(options)
+		if !ok {
+			return ParamInfo{}, false
+		}
+		if opts.structural {
+			class = opts.paramClass()
+		}
+		return ParamInfo{Name: name, Class: class, CaseInsensitive: true}, true
+	}
+
+	// a custom class mustn't add groups of its own or param positions would shift
+	re, err := regexp.Compile(spec)
+	if err != nil || re.NumSubexp() > 0 {
+		return ParamInfo{}, false
+	}
+	if opts.structural {
+		return ParamInfo{Name: name, Class: opts.paramClass()}, true
+	}
+	return ParamInfo{Name: name, Class: spec}, true
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"net/http"
+	"regexp"
+)
+
+// TemplateHandler returns an http.Handler for a single template without a router, for quick one-off servers.
+// Each request path is matched against the template and fn is called with the captured params in order;
+// paths that don't match get a 404.
+func TemplateHandler(template string, fn func(params []string, w http.ResponseWriter, r *http.Request)) http.Handler {
+	pathPattern := regexp.MustCompile(makeRegexPatternStr(template))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		matches := pathPattern.FindStringSubmatch(r.URL.Path)
+		if matches == nil {
+			http.NotFound(w, r)
+			return
+		}
+		// first match is the full match, ignore it
+		fn(matches[1:], w, r)
+	})
+}
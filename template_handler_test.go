@@ -0,0 +1,59 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestTemplateHandler(t *testing.T) {
+	var calledWith []string
+	called := false
+	handler := TemplateHandler("/foo/bar/%s/baz/%s/qux", func(params []string, w http.ResponseWriter, r *http.Request) {
+		called = true
+		calledWith = params
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	t.Run("match", func(t *testing.T) {
+		called, calledWith = false, nil
+
+		req, err := http.NewRequest("GET", "/foo/bar/alpha123/baz/beta456/qux", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusNoContent {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNoContent)
+		}
+
+		expected := []string{"alpha123", "beta456"}
+		if !called || !reflect.DeepEqual(calledWith, expected) {
+			t.Errorf("fn called = %v with %q; want called with %q", called, calledWith, expected)
+		}
+	})
+
+	t.Run("non-match", func(t *testing.T) {
+		called, calledWith = false, nil
+
+		req, err := http.NewRequest("GET", "/foo/bar/alpha123/baz/qux", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusNotFound {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+		}
+
+		if called {
+			t.Error("fn should not be called for a non-matching path")
+		}
+	})
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMakeRegexPatternStrSegmentCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		expected string
+	}{
+		{
+			name:     "two segments",
+			pattern:  "/geo/{coords:2}",
+			expected: "^/geo/(?P<coords>(?:[^/]+/){1}[^/]+)$",
+		},
+		{
+			name:     "three segments mixed with %s",
+			pattern:  "/files/%s/{path:3}",
+			expected: "^/files/([a-zA-Z0-9]+)/(?P<path>(?:[^/]+/){2}[^/]+)$",
+		},
+		{
+			name:     "named single segment",
+			pattern:  "/users/{userID}/posts/%s",
+			expected: "^/users/(?P<userID>[a-zA-Z0-9]+)/posts/([a-zA-Z0-9]+)$",
+		},
+		{
+			name:     "static text passed through as regex",
+			pattern:  `/files/%s\.(json|xml)`,
+			expected: `^/files/([a-zA-Z0-9]+)\.(json|xml)$`,
+		},
+		{
+			name:     "invalid name left untouched",
+			pattern:  "/users/{user-id}",
+			expected: `^/users/{user-id}$`,
+		},
+		{
+			name:     "invalid count left untouched",
+			pattern:  "/geo/{coords:0}",
+			expected: `^/geo/{coords:0}$`,
+		},
+		{
+			name:     "numeric param",
+			pattern:  "/orders/%d",
+			expected: "^/orders/([0-9]+)$",
+		},
+		{
+			name:     "date param",
+			pattern:  "/reports/%t",
+			expected: "^/reports/([0-9]{4}-[0-9]{2}-[0-9]{2})$",
+		},
+		{
+			name:     "custom class",
+			pattern:  "/posts/{slug:[a-z-]+}",
+			expected: "^/posts/(?P<slug>[a-z-]+)$",
+		},
+		{
+			name:     "custom class with braces",
+			pattern:  "/zip/{code:[0-9]{5}}",
+			expected: "^/zip/(?P<code>[0-9]{5})$",
+		},
+		{
+			name:     "custom class with a capturing group left untouched",
+			pattern:  "/posts/{slug:(a|b)}",
+			expected: `^/posts/{slug:(a|b)}$`,
+		},
+		{
+			name:     "case-insensitive enum",
+			pattern:  "/export/{format:pdf|CSV|html,ci}",
+			expected: "^/export/(?P<format>(?i:pdf|csv|html))$",
+		},
+		{
+			name:     "case-insensitive enum with an invalid option left untouched",
+			pattern:  "/export/{format:pdf|c.v,ci}",
+			expected: `^/export/{format:pdf|c.v,ci}$`,
+		},
+		{
+			name:     "invalid custom class left untouched",
+			pattern:  "/posts/{slug:[a-z}",
+			expected: `^/posts/{slug:[a-z}$`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := makeRegexPatternStr(tt.pattern)
+			if result != tt.expected {
+				t.Errorf("makeRegexPatternStr(%q) = %q; want %q", tt.pattern, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCustomRouterSegmentCountParam(t *testing.T) {
+	router := &customRouter{}
+	router.addTemplateRoutes([]string{"/geo/{coords:2}"})
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "two segments captured as one param",
+			path:           "/geo/45.0/-93.0",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Path parameters received:\nParameter 1: 45.0/-93.0\n",
+		},
+		{
+			name:           "one segment is not enough",
+			path:           "/geo/45.0",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+		{
+			name:           "three segments is too many",
+			path:           "/geo/45.0/-93.0/12",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v",
+					status, tt.expectedStatus)
+			}
+
+			if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %q want %q",
+					rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestCustomRouterDelimiter(t *testing.T) {
+	router := &customRouter{Delimiter: '.'}
+	echoParam := func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "Parameter 1: %s\n", getParam(r, 1))
+	}
+	router.HandleFunc("a.%s.c", echoParam)
+	router.HandleFunc("topics.{region:2}.events", echoParam)
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "single param between dots",
+			path:           "a.x.c",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Parameter 1: x\n",
+		},
+		{
+			name:           "param may contain non-alphanumerics other than the delimiter",
+			path:           "a.x-y_z.c",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Parameter 1: x-y_z\n",
+		},
+		{
+			name:           "param cannot span the delimiter",
+			path:           "a.x.y.c",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+		{
+			name:           "static dot is matched literally",
+			path:           "aXxXc",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+		{
+			name:           "segment count uses the delimiter",
+			path:           "topics.eu.west.events",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Parameter 1: eu.west\n",
+		},
+		{
+			name:           "segment count too short",
+			path:           "topics.eu.events",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.URL.Path = tt.path
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v",
+					status, tt.expectedStatus)
+			}
+
+			if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %q want %q",
+					rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
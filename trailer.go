@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// name of the trailer carrying the 1-indexed param, i.e "X-Param-1"
+func paramTrailerName(index int) string {
+	return fmt.Sprintf("X-Param-%d", index)
+}
+
+// creates an http.HandlerFunc like newPathRegexHandler but sends the captured params as HTTP trailers
+// after the body instead of in it, i.e for chunked responses where the param set is only known late
+func newTrailerHandler(routeTemplateStr string) http.HandlerFunc {
+	regexPatternStr := makeRegexPatternStr(routeTemplateStr)
+	pathPattern := regexp.MustCompile(regexPatternStr)
+	numGroups := pathPattern.NumSubexp()
+
+	// trailers must be declared before the body is written
+	trailerNames := make([]string, numGroups)
+	for i := range trailerNames {
+		trailerNames[i] = paramTrailerName(i + 1)
+	}
+	declaredTrailers := strings.Join(trailerNames, ", ")
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		matches := pathPattern.FindStringSubmatch(r.URL.Path)
+		if matches == nil {
+			log.Printf("No matches for pattern '%s' in path '%s'", regexPatternStr, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+
+		if numGroups > 0 {
+			w.Header().Set("Trailer", declaredTrailers)
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "%d path parameters sent as trailers\n", numGroups)
+
+		for i, name := range trailerNames {
+			w.Header().Set(name, matches[i+1])
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTrailerHandler(t *testing.T) {
+	server := httptest.NewServer(newTrailerHandler("/foo/bar/%s/baz/%s/qux"))
+	defer server.Close()
+
+	t.Run("params sent as trailers", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/foo/bar/alpha123/baz/beta456/qux")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", resp.StatusCode, http.StatusOK)
+		}
+
+		// trailers are only available once the body has been fully read
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expectedBody := "2 path parameters sent as trailers\n"
+		if string(body) != expectedBody {
+			t.Errorf("handler returned unexpected body: got %q want %q", body, expectedBody)
+		}
+
+		expectedTrailers := map[string]string{
+			"X-Param-1": "alpha123",
+			"X-Param-2": "beta456",
+		}
+		for name, expected := range expectedTrailers {
+			if got := resp.Trailer.Get(name); got != expected {
+				t.Errorf("unexpected %s trailer: got %q want %q", name, got, expected)
+			}
+		}
+	})
+
+	t.Run("non-matching path", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/foo/bar/alpha123/qux")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("handler returned wrong status code: got %v want %v", resp.StatusCode, http.StatusNotFound)
+		}
+	})
+}
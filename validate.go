@@ -0,0 +1,18 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Validate checks the registered routes for problems that would otherwise only surface at request time,
+// i.e a route whose handler is nil, returning one joined error naming every offending route
+func (r *customRouter) Validate() error {
+	var errs []error
+	for i, rt := range r.routeSnapshot() {
+		if rt.handler == nil {
+			errs = append(errs, fmt.Errorf("route %d '%s' has a nil handler", i+1, rt.template))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNilHandlerRejected(t *testing.T) {
+	tests := []struct {
+		name            string
+		register        func(router *customRouter)
+		expectedMessage string
+	}{
+		{
+			name:            "HandleFunc",
+			register:        func(router *customRouter) { router.HandleFunc("/users/%s", nil) },
+			expectedMessage: "nil handler for template '/users/%s'",
+		},
+		{
+			name: "HandleTemplates",
+			register: func(router *customRouter) {
+				router.HandleTemplates([]string{"/orders/%s"}, func(string) http.HandlerFunc { return nil })
+			},
+			expectedMessage: "nil handler for template '/orders/%s'",
+		},
+		{
+			name:            "HandleRegex",
+			register:        func(router *customRouter) { router.HandleRegex(regexp.MustCompile(`^/files/(.+)$`), nil) },
+			expectedMessage: "nil handler for regex '^/files/(.+)$'",
+		},
+		{
+			name:            "HandleFuncDeprecated",
+			register:        func(router *customRouter) { router.HandleFuncDeprecated("/old/%s", nil, time.Now()) },
+			expectedMessage: "nil handler for template '/old/%s'",
+		},
+		{
+			name: "HandleFuncHeaders",
+			register: func(router *customRouter) {
+				router.HandleFuncHeaders("/cached/%s", nil, map[string]string{"Cache-Control": "max-age=60"})
+			},
+			expectedMessage: "nil handler for template '/cached/%s'",
+		},
+		{
+			name:            "HandleFuncLocalOnly",
+			register:        func(router *customRouter) { router.HandleFuncLocalOnly("/admin/%s", nil) },
+			expectedMessage: "nil handler for template '/admin/%s'",
+		},
+		{
+			name: "HandleFuncScheduled",
+			register: func(router *customRouter) {
+				router.HandleFuncScheduled("/sale/%s", nil, time.Now(), time.Now().Add(time.Hour))
+			},
+			expectedMessage: "nil handler for template '/sale/%s'",
+		},
+		{
+			name: "HandleFuncCircuitBreaker",
+			register: func(router *customRouter) {
+				router.HandleFuncCircuitBreaker("/downstream/%s", nil, CircuitBreakerOptions{})
+			},
+			expectedMessage: "nil handler for template '/downstream/%s'",
+		},
+		{
+			name: "HandleFuncRateLimited",
+			register: func(router *customRouter) {
+				router.HandleFuncRateLimited("/search/%s", nil, 10, time.Minute)
+			},
+			expectedMessage: "nil handler for template '/search/%s'",
+		},
+		{
+			name:            "HandleFuncMinProto",
+			register:        func(router *customRouter) { router.HandleFuncMinProto("/h2/%s", 2, 0, nil) },
+			expectedMessage: "nil handler for template '/h2/%s'",
+		},
+		{
+			name:            "HandleFuncRequireClientCert",
+			register:        func(router *customRouter) { router.HandleFuncRequireClientCert("/mtls/%s", nil) },
+			expectedMessage: "nil handler for template '/mtls/%s'",
+		},
+		{
+			name:            "HandleWS",
+			register:        func(router *customRouter) { router.HandleWS("/ws/{room}", nil) },
+			expectedMessage: "nil handler for template '/ws/{room}'",
+		},
+		{
+			name: "HandleFuncWeighted",
+			register: func(router *customRouter) {
+				router.HandleFuncWeighted("/rollout/%s", []WeightedHandler{
+					{Weight: 90, Handler: func(w http.ResponseWriter, r *http.Request) {}},
+					{Weight: 10},
+				})
+			},
+			expectedMessage: "invalid weighted route '/rollout/%s': variant 1 has a nil handler",
+		},
+		{
+			name:            "HandleFuncCatchAll",
+			register:        func(router *customRouter) { router.HandleFuncCatchAll(nil) },
+			expectedMessage: "nil handler for the catch-all",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := &customRouter{}
+			defer func() {
+				message := fmt.Sprint(recover())
+				if message != tt.expectedMessage {
+					t.Errorf("unexpected panic: got %q want %q", message, tt.expectedMessage)
+				}
+				if len(router.routes) != 0 {
+					t.Errorf("expected the route not registered, got %d routes", len(router.routes))
+				}
+			}()
+			tt.register(router)
+		})
+	}
+
+	t.Run("HandleFuncAll", func(t *testing.T) {
+		router := &customRouter{}
+		errs := router.HandleFuncAll([]RouteSpec{{Method: "GET", Pattern: "/users/%s"}})
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "nil handler for template '/users/%s'") {
+			t.Errorf("unexpected errors: %v", errs)
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	router := &customRouter{}
+	router.HandleFunc("/users/%s", func(w http.ResponseWriter, r *http.Request) {})
+	if err := router.Validate(); err != nil {
+		t.Fatalf("unexpected error validating a router without nil handlers: %v", err)
+	}
+
+	// a route built without going through registration, i.e merged from a hand assembled router
+	other := &customRouter{routes: []*route{{template: "/orders/%s", pattern: regexp.MustCompile(`^/orders/([a-zA-Z0-9]+)$`)}}}
+	if err := router.Merge(other); err != nil {
+		t.Fatal(err)
+	}
+
+	err := router.Validate()
+	if err == nil || err.Error() != "route 2 '/orders/%s' has a nil handler" {
+		t.Errorf("unexpected validation error: %v", err)
+	}
+}
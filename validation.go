@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// HandleFuncValidated registers a route whose captured params are checked by the given validators,
+// keyed by 1-indexed param position, before the handler runs. If a validator fails the request is
+// rejected with a 422 carrying the validator's error message. Params checked by the template itself, like
+// '%t' dates, are checked before the given validators.
+func (r *customRouter) HandleFuncValidated(pattern string, handler http.HandlerFunc, validators map[int]func(string) error) {
+	r.handle(pattern, handler, func(rt *route) {
+		// merged one by one rather than assigning the map: that would drop validators the template
+		// added itself, i.e the date check of '%t', and share the caller's map between routes
+		for index, validator := range validators {
+			rt.addValidator(index, validator)
+		}
+	})
+}
+
+// runs the route's validators against the captured params, in param order so the reported error is stable
+func (rt *route) validateParams(params []string) error {
+	if len(rt.validators) == 0 {
+		return nil
+	}
+
+	indices := make([]int, 0, len(rt.validators))
+	for index := range rt.validators {
+		indices = append(indices, index)
+	}
+	sort.Ints(indices)
+
+	for _, index := range indices {
+		if index < 1 || index > len(params) {
+			return fmt.Errorf("parameter %d: not captured by route", index)
+		}
+		if err := rt.validators[index](params[index-1]); err != nil {
+			return fmt.Errorf("parameter %d: %v", index, err)
+		}
+	}
+	return nil
+}
+
+// ConstrainIntRange requires the 1-indexed param of the routes registered with template to be an integer
+// within [min, max], i.e a month between 1 and 12. Out of range values are rejected with a 422 like any
+// other validator; an existing validator for the param still runs first.
+func (r *customRouter) ConstrainIntRange(template string, index, min, max int) error {
+	routes := r.routesForTemplate(template)
+	if len(routes) == 0 {
+		return fmt.Errorf("no route registered for template '%s'", template)
+	}
+
+	inRange := func(value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+		if n < min || n > max {
+			return fmt.Errorf("must be between %d and %d", min, max)
+		}
+		return nil
+	}
+
+	for _, rt := range routes {
+		rt.addValidator(index, inRange)
+	}
+	return nil
+}
+
+// adds a validator for the 1-indexed param, running after any validator already registered for it
+func (rt *route) addValidator(index int, validator func(string) error) {
+	if rt.validators == nil {
+		rt.validators = make(map[int]func(string) error)
+	}
+
+	existing := rt.validators[index]
+	if existing == nil {
+		rt.validators[index] = validator
+		return
+	}
+
+	rt.validators[index] = func(value string) error {
+		if err := existing(value); err != nil {
+			return err
+		}
+		return validator(value)
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// accepts values whose digits sum to a multiple of 10, a stand-in for a real checksum
+func luhnLikeChecksum(value string) error {
+	sum := 0
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return errors.New("must contain only digits")
+		}
+		sum += int(c - '0')
+	}
+	if sum%10 != 0 {
+		return errors.New("invalid checksum")
+	}
+	return nil
+}
+
+func TestHandleFuncValidated(t *testing.T) {
+	router := &customRouter{}
+	routeTemplateStr := "/accounts/%s/orders/%s"
+	router.HandleFuncValidated(routeTemplateStr, newDynamicPathHandler(routeTemplateStr), map[int]func(string) error{
+		1: luhnLikeChecksum,
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "passing validator",
+			path:           "/accounts/1234/orders/abc",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Path parameters received:\nParameter 1: 1234\nParameter 2: abc\n",
+		},
+		{
+			name:           "failing validator",
+			path:           "/accounts/1235/orders/abc",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "parameter 1: invalid checksum\n",
+		},
+		{
+			name:           "failing validator on non-digits",
+			path:           "/accounts/abcd/orders/abc",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "parameter 1: must contain only digits\n",
+		},
+		{
+			name:           "non-matching path is still a 404",
+			path:           "/accounts/1234/orders",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v",
+					status, tt.expectedStatus)
+			}
+
+			if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %q want %q",
+					rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestConstrainIntRange(t *testing.T) {
+	router := &customRouter{}
+	routeTemplateStr := "/reports/%s/%s"
+	router.HandleFunc(routeTemplateStr, newDynamicPathHandler(routeTemplateStr))
+
+	if err := router.ConstrainIntRange(routeTemplateStr, 2, 1, 12); err != nil {
+		t.Fatalf("unexpected error constraining param: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "in range",
+			path:           "/reports/2024/7",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Path parameters received:\nParameter 1: 2024\nParameter 2: 7\n",
+		},
+		{
+			name:           "range is inclusive",
+			path:           "/reports/2024/12",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Path parameters received:\nParameter 1: 2024\nParameter 2: 12\n",
+		},
+		{
+			name:           "below min",
+			path:           "/reports/2024/0",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "parameter 2: must be between 1 and 12\n",
+		},
+		{
+			name:           "above max",
+			path:           "/reports/2024/13",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "parameter 2: must be between 1 and 12\n",
+		},
+		{
+			name:           "not an integer",
+			path:           "/reports/2024/july",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "parameter 2: must be an integer\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v",
+					status, tt.expectedStatus)
+			}
+
+			if strings.TrimSpace(rr.Body.String()) != strings.TrimSpace(tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %q want %q",
+					rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+
+	if err := router.ConstrainIntRange("/not/registered/%s", 1, 1, 12); err == nil {
+		t.Error("expected an error constraining a param on an unregistered template")
+	}
+}
+
+func TestConstrainIntRangeSharedValidators(t *testing.T) {
+	router := &customRouter{}
+	validators := map[int]func(string) error{}
+	router.HandleFuncValidated("/a/%s", newDynamicPathHandler("/a/%s"), validators)
+	router.HandleFuncValidated("/b/%s", newDynamicPathHandler("/b/%s"), validators)
+
+	if err := router.ConstrainIntRange("/a/%s", 1, 1, 12); err != nil {
+		t.Fatalf("unexpected error constraining param: %v", err)
+	}
+
+	for path, expectedStatus := range map[string]int{
+		"/a/xyz": http.StatusUnprocessableEntity,
+		"/b/xyz": http.StatusOK,
+	} {
+		req, err := http.NewRequest("GET", path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != expectedStatus {
+			t.Errorf("handler returned wrong status code for %s: got %v want %v", path, status, expectedStatus)
+		}
+	}
+	if len(validators) != 0 {
+		t.Errorf("expected the caller's validators left untouched, got %d", len(validators))
+	}
+}
+
+func TestHandleFuncValidatedKeepsDateCheck(t *testing.T) {
+	router := &customRouter{}
+	router.HandleFuncValidated("/reports/%t/%s", newDynamicPathHandler("/reports/%t/%s"), map[int]func(string) error{
+		2: luhnLikeChecksum,
+	})
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+	}{
+		{path: "/reports/2023-02-28/55", expectedStatus: http.StatusOK},
+		{path: "/reports/2023-02-30/55", expectedStatus: http.StatusUnprocessableEntity},
+		{path: "/reports/2023-02-28/56", expectedStatus: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != tt.expectedStatus {
+			t.Errorf("handler returned wrong status code for %s: got %v want %v", tt.path, status, tt.expectedStatus)
+		}
+	}
+}
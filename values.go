@@ -0,0 +1,20 @@
+package main
+
+import (
+	"context"
+	"net/http"
+)
+
+// context key for values stashed with setValue; a distinct type so they never collide with params or
+// other context values using the same string
+type valueKey string
+
+// returns a copy of r carrying val under key, i.e for middleware passing a loaded user to the handler
+func setValue(r *http.Request, key string, val any) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), valueKey(key), val))
+}
+
+// returns the value stored under key by setValue, or nil if there is none
+func getValue(r *http.Request, key string) any {
+	return r.Context().Value(valueKey(key))
+}
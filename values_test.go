@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSetValue(t *testing.T) {
+	router := &customRouter{}
+	router.use(func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			// a value sharing its key with a param name mustn't shadow the param
+			r = setValue(r, "userID", "from-middleware")
+			next(w, setValue(r, "attempts", 3))
+		}
+	})
+	router.HandleFunc("/users/{userID}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%v %v %v %s", getValue(r, "userID"), getValue(r, "attempts"), getValue(r, "missing"),
+			getParamByName(r, "userID"))
+	})
+
+	req, err := http.NewRequest("GET", "/users/u42", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	expected := "from-middleware 3 <nil> u42"
+	if rr.Body.String() != expected {
+		t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), expected)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import "net/http"
+
+// reports whether two routes only differ by the cookie or language they serve, so they're
+// alternatives for the same request
+func (rt *route) sameShape(other *route) bool {
+	// compared by effective method so HandleMethodFunc("GET", ...) and HandleFunc registrations are variants
+	if rt.template != other.template || rt.effectiveMethod() != other.effectiveMethod() {
+		return false
+	}
+	if (rt.host == nil) != (other.host == nil) {
+		return false
+	}
+	return rt.host == nil || rt.host.String() == other.host.String()
+}
+
+// flags rt and the routes it's a variant of as having variants, so only they're grouped when matched;
+// routesMu must be held for writing
+func markVariants(rt *route, routes []*route) {
+	for _, other := range routes {
+		if other != rt && other.sameShape(rt) {
+			other.hasVariants.Store(true)
+			rt.hasVariants.Store(true)
+		}
+	}
+}
+
+// picks the route serving the request among matched and its variants: routes whose cookie the request
+// carries beat cookie-less ones, then 'Accept-Language' decides, see languageVariant.
+// Returns nil when every variant requires a cookie the request doesn't carry.
+func (r *customRouter) selectVariant(matched *route, req *http.Request) *route {
+	if !matched.hasVariants.Load() {
+		if matched.cookie != nil && !hasCookie(req, matched.cookie) {
+			return nil
+		}
+		return matched
+	}
+
+	var withCookie, cookieless []*route
+	for _, rt := range r.routeSnapshot() {
+		if rt != matched && (!rt.sameShape(matched) || !rt.active() || (rt.accepts != nil && !rt.accepts(req))) {
+			continue
+		}
+		switch {
+		case rt.cookie == nil:
+			cookieless = append(cookieless, rt)
+		case hasCookie(req, rt.cookie):
+			withCookie = append(withCookie, rt)
+		}
+	}
+
+	acceptLanguage := req.Header.Get("Accept-Language")
+	switch {
+	case len(withCookie) > 0:
+		return languageVariant(withCookie, acceptLanguage)
+	case len(cookieless) > 0:
+		return languageVariant(cookieless, acceptLanguage)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// HandleFuncMinVersion registers a route only matching requests whose headerName header holds a version
+// of at least minVersion, i.e "X-API-Version: 3". A missing or malformed header counts as version 0;
+// requests below the minimum fall through to later routes, i.e one registered for older clients.
+func (r *customRouter) HandleFuncMinVersion(pattern, headerName string, minVersion int, handler http.HandlerFunc) {
+	r.handle(pattern, handler, func(rt *route) {
+		rt.accepts = func(req *http.Request) bool {
+			return headerVersion(req, headerName) >= minVersion
+		}
+	})
+}
+
+// returns the numeric version in the header, 0 if it's absent or not a non-negative integer
+func headerVersion(req *http.Request, headerName string) int {
+	version, err := strconv.Atoi(strings.TrimSpace(req.Header.Get(headerName)))
+	if err != nil || version < 0 {
+		return 0
+	}
+	return version
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleFuncMinVersion(t *testing.T) {
+	router := &customRouter{}
+	router.HandleFuncMinVersion("/orders/%s", "X-API-Version", 3, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "v3 order", getParam(r, 1))
+	})
+	router.HandleFunc("/orders/%s", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "legacy order", getParam(r, 1))
+	})
+	router.HandleFuncMinVersion("/invoices/%s", "X-API-Version", 2, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "invoice", getParam(r, 1))
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		version        string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "above the minimum", path: "/orders/o1", version: "4", expectedStatus: http.StatusOK, expectedBody: "v3 order o1\n"},
+		{name: "equal to the minimum", path: "/orders/o1", version: "3", expectedStatus: http.StatusOK, expectedBody: "v3 order o1\n"},
+		{name: "below the minimum", path: "/orders/o1", version: "2", expectedStatus: http.StatusOK, expectedBody: "legacy order o1\n"},
+		{name: "missing header", path: "/orders/o1", expectedStatus: http.StatusOK, expectedBody: "legacy order o1\n"},
+		{name: "malformed header", path: "/orders/o1", version: "three", expectedStatus: http.StatusOK, expectedBody: "legacy order o1\n"},
+		{name: "below the minimum without fallback", path: "/invoices/i1", version: "1", expectedStatus: http.StatusNotFound, expectedBody: "404 page not found\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.version != "" {
+				req.Header.Set("X-API-Version", tt.version)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
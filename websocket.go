@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+)
+
+// HandleWS registers a WebSocket endpoint on a templated path, i.e "/ws/{room}". It doesn't implement
+// the WebSocket protocol: upgrade requests are passed with the captured params to fn, which is expected
+// to hand them to an upgrader. Requests that aren't WebSocket upgrades get a 400.
+func (r *customRouter) HandleWS(pattern string, fn func(params []string, w http.ResponseWriter, r *http.Request)) {
+	if fn == nil {
+		panic(nilHandlerError(pattern))
+	}
+	r.handle(pattern, func(w http.ResponseWriter, req *http.Request) {
+		if !isWebSocketUpgrade(req) {
+			r.writeError(w, http.StatusBadRequest, "Bad request: expected a WebSocket upgrade")
+			return
+		}
+
+		ordered := getAllParamsOrdered(req)
+		params := make([]string, len(ordered))
+		for i, param := range ordered {
+			params[i] = param.Value
+		}
+		fn(params, w, req)
+	})
+}
+
+// reports whether the request asks to switch to the WebSocket protocol
+func isWebSocketUpgrade(r *http.Request) bool {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return false
+	}
+
+	// 'Connection' is a comma separated list of tokens, i.e "keep-alive, Upgrade"
+	for _, value := range r.Header.Values("Connection") {
+		for _, token := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+				return true
+			}
+		}
+	}
+	return false
+}
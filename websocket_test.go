@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// a ResponseRecorder that can be hijacked like a server connection, handing the upgrader one end of a pipe
+type hijackableRecorder struct {
+	*httptest.ResponseRecorder
+	server, client net.Conn
+}
+
+func newHijackableRecorder() *hijackableRecorder {
+	server, client := net.Pipe()
+	return &hijackableRecorder{ResponseRecorder: httptest.NewRecorder(), server: server, client: client}
+}
+
+func (h *hijackableRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return h.server, bufio.NewReadWriter(bufio.NewReader(h.server), bufio.NewWriter(h.server)), nil
+}
+
+func TestHandleWS(t *testing.T) {
+	var gotParams []string
+	router := &customRouter{}
+	router.HandleWS("/ws/{room}/%s", func(params []string, w http.ResponseWriter, r *http.Request) {
+		gotParams = params
+
+		// take over the connection the way an upgrader like gorilla/websocket's does
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			t.Errorf("%T passed to the upgrader isn't an http.Hijacker", w)
+			return
+		}
+		conn, rw, err := hijacker.Hijack()
+		if err != nil {
+			t.Errorf("unexpected error hijacking: %v", err)
+			return
+		}
+		go func() {
+			defer conn.Close()
+			rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+			rw.Flush()
+		}()
+	})
+
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		expectUpgrade  bool
+		expectedStatus int
+		expectedParams []string
+	}{
+		{
+			name: "upgrade request",
+			headers: map[string]string{
+				"Connection": "Upgrade",
+				"Upgrade":    "websocket",
+			},
+			expectUpgrade:  true,
+			expectedParams: []string{"lobby", "user1"},
+		},
+		{
+			name: "upgrade among other connection tokens",
+			headers: map[string]string{
+				"Connection": "keep-alive, upgrade",
+				"Upgrade":    "WebSocket",
+			},
+			expectUpgrade:  true,
+			expectedParams: []string{"lobby", "user1"},
+		},
+		{
+			name:           "plain request",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "upgrade to another protocol",
+			headers: map[string]string{
+				"Connection": "Upgrade",
+				"Upgrade":    "h2c",
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotParams = nil
+
+			req, err := http.NewRequest("GET", "/ws/lobby/user1", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			rr := newHijackableRecorder()
+			defer rr.client.Close()
+			router.ServeHTTP(rr, req)
+
+			if tt.expectUpgrade {
+				resp, err := http.ReadResponse(bufio.NewReader(rr.client), req)
+				if err != nil {
+					t.Fatalf("reading the upgrade response from the hijacked connection: %v", err)
+				}
+				if resp.StatusCode != http.StatusSwitchingProtocols {
+					t.Errorf("hijacked connection got status %d; want %d", resp.StatusCode, http.StatusSwitchingProtocols)
+				}
+			} else if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			if !reflect.DeepEqual(gotParams, tt.expectedParams) {
+				t.Errorf("unexpected params passed to the upgrader: got %q want %q", gotParams, tt.expectedParams)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+)
+
+// WeightedHandler is a variant of a HandleFuncWeighted route, chosen for about Weight out of every
+// total-weight requests
+type WeightedHandler struct {
+	Weight  int
+	Handler http.HandlerFunc
+}
+
+// HandleFuncWeighted registers a route serving each request with one of variants picked at random by
+// weight, i.e weights 90 and 10 for a gradual rollout of a new handler. Picks use the router's Rand.
+// It panics if a variant's handler is nil, a weight is negative or none is positive, like other invalid
+// registrations.
+func (r *customRouter) HandleFuncWeighted(pattern string, variants []WeightedHandler) {
+	total := 0
+	for i, v := range variants {
+		if v.Handler == nil {
+			panic(fmt.Errorf("invalid weighted route '%s': variant %d has a nil handler", pattern, i))
+		}
+		if v.Weight < 0 {
+			panic(fmt.Errorf("invalid weighted route '%s': variant %d has negative weight %d", pattern, i, v.Weight))
+		}
+		total += v.Weight
+	}
+	if total == 0 {
+		panic(fmt.Errorf("invalid weighted route '%s': no variant has a positive weight", pattern))
+	}
+
+	r.handle(pattern, func(w http.ResponseWriter, req *http.Request) {
+		pick := r.randIntN(total)
+		for _, v := range variants {
+			if pick < v.Weight {
+				v.Handler(w, req)
+				return
+			}
+			pick -= v.Weight
+		}
+	})
+}
+
+// returns a random int in [0, n) from Rand, serialized since a rand.Rand isn't safe for concurrent use
+func (r *customRouter) randIntN(n int) int {
+	r.randMu.Lock()
+	defer r.randMu.Unlock()
+	if r.Rand == nil {
+		r.Rand = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	return r.Rand.IntN(n)
+}
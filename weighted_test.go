@@ -0,0 +1,67 @@
+package main
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleFuncWeighted(t *testing.T) {
+	router := &customRouter{Rand: rand.New(rand.NewPCG(1, 2))}
+
+	counts := make(map[string]int)
+	variant := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			counts[name]++
+		}
+	}
+	router.HandleFuncWeighted("/checkout/%s", []WeightedHandler{
+		{Weight: 90, Handler: variant("old")},
+		{Weight: 10, Handler: variant("new")},
+		{Weight: 0, Handler: variant("disabled")},
+	})
+
+	const requests = 10000
+	for range requests {
+		req, err := http.NewRequest("GET", "/checkout/c1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if counts["old"]+counts["new"] != requests {
+		t.Fatalf("expected every request served by a variant, got %v", counts)
+	}
+	if counts["disabled"] != 0 {
+		t.Errorf("a zero weight variant served %d requests", counts["disabled"])
+	}
+	// 10% of 10000 is 1000; allow for sampling noise
+	if counts["new"] < 900 || counts["new"] > 1100 {
+		t.Errorf("new variant served %d of %d requests; want about 10%%", counts["new"], requests)
+	}
+}
+
+func TestHandleFuncWeightedInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		variants []WeightedHandler
+	}{
+		{name: "no variants"},
+		{name: "all zero", variants: []WeightedHandler{{Weight: 0}}},
+		{name: "negative", variants: []WeightedHandler{{Weight: 5}, {Weight: -1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected a panic registering invalid weights")
+				}
+			}()
+			router := &customRouter{}
+			router.HandleFuncWeighted("/checkout/%s", tt.variants)
+		})
+	}
+}